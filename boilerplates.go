@@ -40,7 +40,7 @@ func boilerplates(args []string) {
 }
 
 func getBoilerplate(url string) error {
-	resp, ok, err := utils.UrlExists(url)
+	resp, ok, _, err := utils.UrlExists(url)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	ui "github.com/manifoldco/promptui"
+)
+
+// confirm asks the user to confirm label, unless --yes was given.
+func confirm(label string) bool {
+	if assumeYes {
+		return true
+	}
+	prompt := ui.Prompt{Label: label, IsConfirm: true}
+	_, err := prompt.Run()
+	return err == nil
+}
@@ -1,17 +1,31 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"github.com/mhmorgan/rogu/items"
 	log "github.com/mhmorgan/termlog"
 	"github.com/spf13/cobra"
 )
 
+var (
+	doctorOnly      string
+	doctorFix       string
+	doctorReinstall bool
+)
+
 func init() {
 	RootCmd.AddCommand(doctorCmd)
+
+	flags := doctorCmd.Flags()
+	flags.StringVar(&doctorOnly, "only", "", "only report items matching status: tainted, missing or outdated")
+	flags.StringVar(&doctorFix, "fix", "", "try to fix issues as soon as they're encountered; pass \"stash\" to stash dirty dotfiles without asking")
+	flags.Lookup("fix").NoOptDefVal = "true"
+	flags.BoolVar(&doctorReinstall, "reinstall", false, "fix by uninstalling and reinstalling, instead of just installing")
 }
 
 var doctorCmd = &cobra.Command{
-	Use:     "doctor",
+	Use:     "doctor [filters...]",
 	Aliases: []string{"d"},
 	Short:   "Doctor is a tool for diagnosing common problems with your Rogu installation",
 	Long: `Doctor is a tool for diagnosing common problems.
@@ -22,34 +36,214 @@ system. This is mostly a passive action, but not completely.
 The optional FILTER allows filtering on which sanity checks
 are performed, selecting on title.
 
+The --only flag filters by status, reporting only tainted,
+missing or outdated items.
+
 When --fix is given Rogu tries to fix any issues as soon as
-it is encountered, if he knows how.`,
+it is encountered, if he knows how. --reinstall additionally
+uninstalls a broken item before reinstalling it.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		items_, err := items.All()
 		if err != nil {
 			log.Fatal(err)
 		}
+		checkFilters(items_, args)
 
 		for _, item := range items_ {
-			if item.IsInstalled == nil {
-				log.Errorf("%s has no installation check", item.Name)
+			if !nameMatch(item.Name(), args) {
 				continue
 			}
-			switch installed, err := item.IsInstalled(); {
-			case err != nil:
-				log.Errorf("%v IsInstalled: %v", item.Name, err)
-			case !installed && item.Install == nil:
-				log.Badf("%v is not installed and I don't know how to install it!", item.Name)
-			case !installed:
-				log.Emphf("Not installed: %v", item.Name)
-			case installed:
-				if item.Update != nil {
-					log.Goodf("Installed & updated: %v", item.Name)
-				} else {
-					log.Goodf("Installed: %v", item.Name)
-				}
-			}
+			doctorCheck(item)
 		}
 	},
 }
+
+func doctorCheck(item items.Item) {
+	src := sourceLabel(item)
+
+	var tainted bool
+	if tainter, ok := item.(items.Tainted); ok {
+		var err error
+		if tainted, err = tainter.IsTainted(); err != nil {
+			log.Errorf("%v%s IsTainted: %v", item, src, err)
+			return
+		}
+	}
+
+	installed, err := item.IsInstalled()
+	if err != nil {
+		log.Errorf("%v%s IsInstalled: %v", item, src, err)
+		return
+	}
+	missing := !installed
+
+	if !doctorMatchesOnly(item, missing, tainted, src) {
+		return
+	}
+
+	switch {
+	case tainted:
+		log.Emphf("%v%s ... tainted (modified locally)", item, src)
+		if doctorFix != "" {
+			fixTainted(item)
+		}
+	case missing:
+		log.Badf("%v%s ... missing", item, src)
+		if doctorFix != "" {
+			fixMissing(item)
+		}
+	default:
+		if err := item.Check(); err != nil {
+			log.Badf("%v%s ... %v", item, src, err)
+			if doctorFix != "" {
+				fixGeneric(item)
+			}
+		} else {
+			log.Goodf("%v%s ... OK", item, src)
+		}
+	}
+}
+
+// sourceLabel returns a "[path/to/manifest.yaml]" suffix naming the
+// plugin manifest which declared item, when --verbose is set and the
+// item came from one.
+func sourceLabel(item items.Item) string {
+	if !verbose {
+		return ""
+	}
+	if s, ok := item.(items.Sourced); ok {
+		if src := s.Source(); src != "" {
+			return fmt.Sprintf(" [%s]", src)
+		}
+	}
+	return ""
+}
+
+// doctorMatchesOnly reports whether item, with the given missing/tainted
+// status, should be printed, according to the --only flag.
+func doctorMatchesOnly(item items.Item, missing, tainted bool, src string) bool {
+	switch doctorOnly {
+	case "":
+		return true
+	case "missing":
+		return missing
+	case "tainted":
+		return tainted
+	case "outdated":
+		return isOutdated(item, src)
+	default:
+		log.Fatalf("unknown --only filter %q (want tainted, missing or outdated)", doctorOnly)
+		return false
+	}
+}
+
+// isOutdated reports whether item has a newer version available
+// upstream than the one it currently has installed. Items which
+// aren't Versioned, or have no way to resolve an upstream version,
+// are never outdated.
+func isOutdated(item items.Item, src string) bool {
+	v, ok := item.(items.Versioned)
+	if !ok {
+		return false
+	}
+	installed, err := v.Version()
+	if err != nil {
+		log.Errorf("%v%s Version: %v", item, src, err)
+		return false
+	}
+	latest, err := v.LatestVersion()
+	var noVersion items.NoVersionError
+	if errors.As(err, &noVersion) {
+		return false
+	} else if err != nil {
+		log.Errorf("%v%s LatestVersion: %v", item, src, err)
+		return false
+	}
+	return installed != latest
+}
+
+func fixMissing(item items.Item) {
+	if !confirm(fmt.Sprintf("Install %v?", item)) {
+		return
+	}
+	log.Emphf("Installing %v", item)
+	var noInstaller items.NoInstallerError
+	if err := item.Install(); errors.As(err, &noInstaller) {
+		log.Warnf("I don't know how to install %v", item)
+	} else if err != nil {
+		log.Error(err)
+	}
+}
+
+func fixTainted(item items.Item) {
+	label := fmt.Sprintf("Overwrite tainted %v?", item)
+	if doctorReinstall {
+		label = fmt.Sprintf("Uninstall and reinstall tainted %v?", item)
+	}
+	if !confirm(label) {
+		return
+	}
+	if doctorReinstall {
+		log.Emphf("Uninstalling %v", item)
+		var noUninstaller items.NoUninstallerError
+		if err := item.Uninstall(); err != nil && !errors.As(err, &noUninstaller) {
+			log.Error(err)
+			return
+		}
+	}
+	log.Emphf("Reinstalling %v", item)
+	var noInstaller items.NoInstallerError
+	if err := item.Install(); errors.As(err, &noInstaller) {
+		log.Warnf("I don't know how to reinstall %v", item)
+	} else if err != nil {
+		log.Error(err)
+	}
+}
+
+// fixGeneric handles a plain Check failure which isn't categorized as
+// missing or tainted, e.g. uncommitted dotfiles.
+func fixGeneric(item items.Item) {
+	if stasher, ok := item.(items.Stasher); ok {
+		fixStash(item, stasher)
+		return
+	}
+	if !doctorReinstall {
+		return
+	}
+	if !confirm(fmt.Sprintf("Reinstall %v?", item)) {
+		return
+	}
+	log.Emphf("Uninstalling %v", item)
+	var noUninstaller items.NoUninstallerError
+	if err := item.Uninstall(); err != nil && !errors.As(err, &noUninstaller) {
+		log.Error(err)
+		return
+	}
+	log.Emphf("Reinstalling %v", item)
+	var noInstaller items.NoInstallerError
+	if err := item.Install(); errors.As(err, &noInstaller) {
+		log.Warnf("I don't know how to reinstall %v", item)
+	} else if err != nil {
+		log.Error(err)
+	}
+}
+
+func fixStash(item items.Item, stasher items.Stasher) {
+	diff, err := stasher.Diff()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if diff == "" {
+		return
+	}
+	fmt.Println(diff)
+	if doctorFix != "stash" && !confirm(fmt.Sprintf("Stash changes in %v?", item)) {
+		return
+	}
+	log.Emphf("Stashing changes in %v", item)
+	if err := stasher.Stash(); err != nil {
+		log.Error(err)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/mhmorgan/rogu/hub"
+	"github.com/mhmorgan/rogu/items"
+	log "github.com/mhmorgan/termlog"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	RootCmd.AddCommand(searchCmd, showCmd, installCmd)
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <substr>",
+	Short: "Search the hub index for items whose name matches substr",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		idx, err := hub.Fetch()
+		if err != nil {
+			log.Fatal(err)
+		}
+		names := hub.Search(idx, args[0])
+		if len(names) == 0 {
+			log.Badf("no hub items match %q", args[0])
+			return
+		}
+		for _, name := range names {
+			fmt.Printf("%s (%s)\n", name, idx[name].Type)
+		}
+	},
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a hub index entry",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		idx, err := hub.Fetch()
+		if err != nil {
+			log.Fatal(err)
+		}
+		entry, ok := idx[args[0]]
+		if !ok {
+			log.Fatalf("no hub entry named %q", args[0])
+		}
+		b, err := yaml.Marshal(map[string]hub.Entry{args[0]: entry})
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(string(b))
+	},
+}
+
+var installCmd = &cobra.Command{
+	Use:   "install <name>",
+	Short: "Install an item from the hub",
+	Long: `Install fetches the hub index, looks up name, and adds it to
+the managed ~/.rogu.d/hub.yaml plugin manifest, then installs it the
+same way sync would.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		idx, err := hub.Fetch()
+		if err != nil {
+			log.Fatal(err)
+		}
+		entry, ok := idx[name]
+		if !ok {
+			log.Fatalf("no hub entry named %q", name)
+		}
+
+		if err := hub.Add(name, entry); err != nil {
+			log.Fatal(err)
+		}
+
+		items_, err := items.All()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, item := range items_ {
+			if item.Name() == name {
+				if err := syncItem(item); err != nil {
+					log.Fatal(err)
+				}
+				return
+			}
+		}
+		log.Fatalf("%s was added to %s but couldn't be found among Rogu's items", name, hub.ManifestPath())
+	},
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mhmorgan/rogu/items"
+	log "github.com/mhmorgan/termlog"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+func init() {
+	RootCmd.AddCommand(outdatedCmd)
+}
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated [filters...]",
+	Short: "Report items with a newer upstream version",
+	Long: `Outdated reports items whose installed version differs
+from the latest version available upstream.
+
+The optional FILTER allows filtering on which items are
+checked, selecting on name.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		itms, err := items.All()
+		if err != nil {
+			log.Fatal(err)
+		}
+		checkFilters(itms, args)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tINSTALLED\tLATEST\tDEPENDENCIES")
+		for _, item := range itms {
+			if !nameMatch(item.Name(), args) {
+				continue
+			}
+			v, ok := item.(items.Versioned)
+			if !ok {
+				continue
+			}
+
+			installed, err := v.Version()
+			if err != nil {
+				log.Errorf("%v Version: %v", item, err)
+				continue
+			}
+			latest, err := v.LatestVersion()
+			var noVersion items.NoVersionError
+			if errors.As(err, &noVersion) {
+				continue
+			} else if err != nil {
+				log.Errorf("%v LatestVersion: %v", item, err)
+				continue
+			}
+			if installed == latest {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Name(), installed, latest, strings.Join(item.Dependencies(), ", "))
+		}
+		w.Flush()
+	},
+}
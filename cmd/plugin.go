@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/mhmorgan/rogu/items"
+	log "github.com/mhmorgan/termlog"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(pluginCmd)
+	pluginCmd.AddCommand(pluginListCmd, pluginPathCmd)
+}
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage Rogu's plugin directory",
+	Long: `Plugin manages the directory Rogu scans for externally
+declared items, so third parties can add File/Script/Template
+items without editing the main config.`,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the plugin manifest files Rogu will load",
+	Run: func(cmd *cobra.Command, args []string) {
+		files, err := items.PluginFiles()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, file := range files {
+			fmt.Println(file)
+		}
+	},
+}
+
+var pluginPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the plugin directory path",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(items.PluginDir())
+	},
+}
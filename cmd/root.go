@@ -2,22 +2,24 @@ package cmd
 
 import (
 	"github.com/mhmorgan/rogu/config"
-	"github.com/mhmorgan/rogu/fs"
+	"github.com/mhmorgan/rogu/utils"
 	log "github.com/mhmorgan/termlog"
 	"github.com/spf13/cobra"
 	"os"
 )
 
 var (
-	cfgFile string
-	verbose bool
+	cfgFile   string
+	verbose   bool
+	assumeYes bool
 )
 
 func init() {
 	cobra.OnInitialize(initConfig)
 	pf := RootCmd.PersistentFlags()
-	pf.StringVar(&cfgFile, "config", fs.RelHome(".rogu"), "config file")
+	pf.StringVar(&cfgFile, "config", utils.RelHome(".rogu"), "config file")
 	pf.BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	pf.BoolVarP(&assumeYes, "yes", "y", false, "don't ask for confirmation before changing anything")
 }
 
 func initConfig() {
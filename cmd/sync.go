@@ -1,18 +1,21 @@
 package cmd
 
 import (
-	"fmt"
+	"errors"
 	"github.com/mhmorgan/rogu/items"
 	log "github.com/mhmorgan/termlog"
 	"github.com/spf13/cobra"
 )
 
+var syncForce bool
+
 func init() {
 	RootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().BoolVar(&syncForce, "force", false, "overwrite tainted (locally modified) files")
 }
 
 var syncCmd = &cobra.Command{
-	Use:     "sync",
+	Use:     "sync [filters...]",
 	Aliases: []string{"s"},
 	Short:   "Sync is a tool for syncing your system",
 	Long: `Sync is a tool for syncing your system.
@@ -27,7 +30,11 @@ It installs and updates:
 		if err != nil {
 			log.Fatal(err)
 		}
+		checkFilters(items_, args)
 		for _, item := range items_ {
+			if !nameMatch(item.Name(), args) {
+				continue
+			}
 			if err := syncItem(item); err != nil {
 				log.Fatal(err)
 			}
@@ -36,25 +43,57 @@ It installs and updates:
 }
 
 func syncItem(item items.Item) error {
-	if item.IsInstalled == nil {
-		return fmt.Errorf("%s has no installation check", item.Name)
-	}
 	installed, err := item.IsInstalled()
 	if err != nil {
 		return err
 	}
-	if installed && item.Update != nil {
-		log.Emphf("Updating %s (%d)", item.Name, item.Priority)
-		return item.Update()
-	}
-	if !installed {
-		if item.Install != nil {
-			log.Emphf("Installing %s (%d)", item.Name, item.Priority)
-			return item.Install()
-		} else {
-			log.Warnf("I don't know how to install %s", item.Name)
+
+	if installed {
+		if tainter, ok := item.(items.Tainted); ok && !syncForce {
+			tainted, err := tainter.IsTainted()
+			if err != nil {
+				return err
+			}
+			if tainted {
+				log.Warnf("%s is tainted (modified locally), skipping update (use --force to overwrite)", item)
+				return nil
+			}
+		}
+		err := withHooks(item, items.Hooked.PreUpdate, items.Hooked.PostUpdate, item.Update)
+		var noUpdater items.NoUpdaterError
+		if errors.As(err, &noUpdater) {
 			return nil
+		} else if err != nil {
+			return err
 		}
+		log.Emphf("Updated %s (%d)", item.Name(), item.Priority())
+		return nil
+	}
+
+	err = withHooks(item, items.Hooked.PreInstall, items.Hooked.PostInstall, item.Install)
+	var noInstaller items.NoInstallerError
+	if errors.As(err, &noInstaller) {
+		log.Warnf("I don't know how to install %s", item.Name())
+		return nil
+	} else if err != nil {
+		return err
 	}
+	log.Emphf("Installed %s (%d)", item.Name(), item.Priority())
 	return nil
 }
+
+// withHooks runs action wrapped by item's pre/post hooks for the
+// given lifecycle stage, if item implements items.Hooked.
+func withHooks(item items.Item, pre, post func(items.Hooked) error, action func() error) error {
+	hooked, ok := item.(items.Hooked)
+	if !ok {
+		return action()
+	}
+	if err := pre(hooked); err != nil {
+		return err
+	}
+	if err := action(); err != nil {
+		return err
+	}
+	return post(hooked)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mhmorgan/rogu/items"
+	log "github.com/mhmorgan/termlog"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(uninstallCmd)
+}
+
+var uninstallCmd = &cobra.Command{
+	Use:     "uninstall <filters...>",
+	Aliases: []string{"rm"},
+	Short:   "Uninstall one or more of Rogu's items",
+	Long: `Uninstall removes items Rogu knows how to uninstall.
+
+At least one FILTER must be given, selecting items by name,
+to avoid accidentally uninstalling everything.`,
+
+	Args: cobra.MinimumNArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		items_, err := items.All()
+		if err != nil {
+			log.Fatal(err)
+		}
+		checkFilters(items_, args)
+
+		for _, item := range items_ {
+			if nameMatch(item.Name(), args) {
+				uninstallItem(item)
+			}
+		}
+	},
+}
+
+func uninstallItem(item items.Item) {
+	if !confirm(fmt.Sprintf("Uninstall %v?", item)) {
+		return
+	}
+	log.Emphf("Uninstalling %v", item)
+	err := withHooks(item, items.Hooked.PreUninstall, items.Hooked.PostUninstall, item.Uninstall)
+	var noUninstaller items.NoUninstallerError
+	if errors.As(err, &noUninstaller) {
+		log.Warnf("I don't know how to uninstall %v", item)
+	} else if err != nil {
+		log.Error(err)
+	}
+}
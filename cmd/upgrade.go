@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/mhmorgan/rogu/items"
+	log "github.com/mhmorgan/termlog"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(upgradeCmd)
+}
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [filters...]",
+	Short: "Upgrade outdated items",
+	Long: `Upgrade re-runs Update for items whose installed version
+differs from the latest version available upstream.
+
+The optional FILTER allows filtering on which items are
+upgraded, selecting on name.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		itms, err := items.All()
+		if err != nil {
+			log.Fatal(err)
+		}
+		checkFilters(itms, args)
+
+		for _, item := range itms {
+			if !nameMatch(item.Name(), args) {
+				continue
+			}
+			v, ok := item.(items.Versioned)
+			if !ok {
+				continue
+			}
+
+			installed, err := v.Version()
+			if err != nil {
+				log.Errorf("%v Version: %v", item, err)
+				continue
+			}
+			latest, err := v.LatestVersion()
+			var noVersion items.NoVersionError
+			if errors.As(err, &noVersion) {
+				continue
+			} else if err != nil {
+				log.Errorf("%v LatestVersion: %v", item, err)
+				continue
+			}
+			if installed == latest {
+				continue
+			}
+
+			log.Emphf("Upgrading %v (%s -> %s)", item, installed, latest)
+			err = withHooks(item, items.Hooked.PreUpdate, items.Hooked.PostUpdate, item.Update)
+			var noUpdater items.NoUpdaterError
+			if errors.As(err, &noUpdater) {
+				log.Warnf("I don't know how to update %v", item)
+			} else if err != nil {
+				log.Error(err)
+			}
+		}
+	},
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/mhmorgan/rogu/items"
+	log "github.com/mhmorgan/termlog"
+	"os"
+	"strings"
+)
+
+// nameMatch reports whether name contains any of ss, case-insensitively.
+// An empty ss matches everything.
+func nameMatch(name string, ss []string) bool {
+	if len(ss) == 0 {
+		return true
+	}
+	name = strings.ToLower(name)
+	for _, s := range ss {
+		if strings.Contains(name, strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFilters exits with a "did you mean?" suggestion for the first
+// filter in args which matches no item in itms.
+func checkFilters(itms []items.Item, args []string) {
+	for _, arg := range args {
+		matched := false
+		for _, item := range itms {
+			if nameMatch(item.Name(), []string{arg}) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if suggestion := items.SuggestNearest(arg); suggestion != "" {
+			log.Errorf("can't find %q, did you mean %q?", arg, suggestion)
+		} else {
+			log.Errorf("can't find %q", arg)
+		}
+		os.Exit(1)
+	}
+}
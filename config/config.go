@@ -25,39 +25,137 @@ type Config struct {
 		UrlDir string `yaml:"url_dir"`
 		Binary string `yaml:"-"`
 		Path   string
+		// GitBackend selects the git implementation Repo uses: "cli"
+		// (default) shells out to the git binary, "native" uses a
+		// pure-Go implementation so systems without git installed can
+		// still sync dotfiles and templates.
+		GitBackend string `yaml:"git_backend"`
 	}
 
 	Dotfiles struct {
 		Url    string // Git URL
 		Branch string // Optional
+		// Atomic makes Sync stage updates in a scratch git worktree
+		// and only copy them into $HOME once they apply cleanly, so a
+		// conflicting pull or a dirty file can't leave $HOME half
+		// updated.
+		Atomic bool
 	}
 
 	Boilerplates struct {
 		UrlDir string `yaml:"url_dir"`
 	}
 
-	Scripts map[string]struct {
-		Priority          int    // Optional
-		Check             string // Defaults to IsInstalled
-		IsInstalled       string
-		Install           string
-		Uninstall         string // Optional
-		Update            string // Optional
-		UpdateWithInstall bool   `yaml:"update_with_install"` // Optional
+	Plugins struct {
+		// Dir overrides the default plugin directory (~/.rogu.d),
+		// itself overridable with the ROGU_PLUGIN_DIR env var.
+		Dir string
 	}
 
-	Files map[string]struct {
-		Priority int    // Optional
-		Url      string // File URL
-		Dst      string // File path
-		Mode     int    // Optional
+	// Hub configures the remote item catalog used by the search, show
+	// and install commands.
+	Hub struct {
+		// IndexUrl overrides the default hub index URL.
+		IndexUrl string `yaml:"index_url"`
 	}
 
-	Templates map[string]struct {
-		Priority int    // Optional
-		Url      string // Git URL
-		Branch   string // Optional
+	// Auth configures fallback HTTPS credentials for git operations
+	// (dotfiles/template clones), keyed by host. Entries here override
+	// whatever ~/.netrc has for the same host, so a fresh machine can
+	// bootstrap private repos without a pre-configured credential
+	// helper.
+	Auth struct {
+		Hosts map[string]AuthHost `yaml:"hosts"`
 	}
+
+	Scripts map[string]ScriptEntry
+
+	Files map[string]FileEntry
+
+	Templates map[string]TemplateEntry
+
+	// Collections bundle a list of member item names, similar to how
+	// CrowdSec's cwhub collections group parsers/scenarios/postoverflows.
+	// Uninstalling a collection delegates to its members, in dependency
+	// order. Installing or updating one does not: its members are
+	// installed and updated as items in their own right.
+	Collections map[string]CollectionEntry
+}
+
+type ScriptEntry struct {
+	Priority          int    // Optional
+	Check             string // Defaults to IsInstalled
+	IsInstalled       string
+	Install           string
+	Uninstall         string   // Optional
+	Update            string   // Optional
+	UpdateWithInstall bool     `yaml:"update_with_install"` // Optional
+	Dependencies      []string // Optional, names of items this one depends on
+	DependsOn         []string `yaml:"depends_on"` // Alias for Dependencies
+	Hooks             `yaml:",inline"`
+}
+
+type FileEntry struct {
+	Priority     int      // Optional
+	Url          string   // File URL
+	Dst          string   // File path
+	Mode         int      // Optional
+	Dependencies []string // Optional, names of items this one depends on
+	DependsOn    []string `yaml:"depends_on"` // Alias for Dependencies
+	Sha256       string   // Optional, verified before the downloaded file is installed
+	// VersionUrl points to a plain text file holding the current
+	// upstream version, used by "rogu outdated"/"rogu upgrade". If
+	// empty and Url is a GitHub releases URL, the latest release tag
+	// is used instead.
+	VersionUrl string `yaml:"version_url"`
+	Hooks      `yaml:",inline"`
+}
+
+type TemplateEntry struct {
+	Priority     int      // Optional
+	Url          string   // Git URL
+	Branch       string   // Optional
+	Dependencies []string // Optional, names of items this one depends on
+	DependsOn    []string `yaml:"depends_on"` // Alias for Dependencies
+	Hooks        `yaml:",inline"`
+}
+
+// Deps returns e's declared dependencies, accepting depends_on as an
+// alias for dependencies.
+func (e ScriptEntry) Deps() []string { return append(e.Dependencies, e.DependsOn...) }
+
+// Deps returns e's declared dependencies, accepting depends_on as an
+// alias for dependencies.
+func (e FileEntry) Deps() []string { return append(e.Dependencies, e.DependsOn...) }
+
+// Deps returns e's declared dependencies, accepting depends_on as an
+// alias for dependencies.
+func (e TemplateEntry) Deps() []string { return append(e.Dependencies, e.DependsOn...) }
+
+// Hooks are the optional shell commands run around an item's
+// install/update/uninstall, embedded by the entry types that support
+// them. All are optional and default to a no-op.
+type Hooks struct {
+	PreInstall    string `yaml:"pre_install"`
+	PostInstall   string `yaml:"post_install"`
+	PreUpdate     string `yaml:"pre_update"`
+	PostUpdate    string `yaml:"post_update"`
+	PreUninstall  string `yaml:"pre_uninstall"`
+	PostUninstall string `yaml:"post_uninstall"`
+}
+
+// AuthHost names the environment variables holding the username and
+// token/password to use for a host, e.g. github.com: { user_env:
+// GH_USER, token_env: GH_TOKEN }.
+type AuthHost struct {
+	UserEnv  string `yaml:"user_env"`
+	TokenEnv string `yaml:"token_env"`
+}
+
+type CollectionEntry struct {
+	Priority     int      // Optional
+	Items        []string // Member item names
+	Dependencies []string // Optional, extra dependencies beyond the members
 }
 
 func Load(r io.Reader) error {
@@ -1,14 +1,23 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	ui "github.com/manifoldco/promptui"
 	"github.com/mhmorgan/rogu/config"
 	"github.com/mhmorgan/rogu/items"
 	log "github.com/mhmorgan/termlog"
 	"os"
 )
 
+var (
+	only      string
+	fix       bool
+	reinstall bool
+	yes       bool
+)
+
 func init() {
 	commands["doctor"] = &command{doctorFlags, doctor}
 }
@@ -19,6 +28,20 @@ func doctorFlags(flags *flag.FlagSet) {
 		flags.PrintDefaults()
 		os.Exit(2)
 	}
+	flags.StringVar(&only, "only", "", "only report items matching status: tainted, missing or outdated")
+	flags.BoolVar(&fix, "fix", false, "try to fix issues as soon as they're encountered")
+	flags.BoolVar(&reinstall, "reinstall", false, "fix by uninstalling and reinstalling, instead of just installing")
+	flags.BoolVar(&yes, "yes", false, "don't ask for confirmation before changing anything")
+}
+
+// confirm asks the user to confirm label, unless -yes was given.
+func confirm(label string) bool {
+	if yes {
+		return true
+	}
+	prompt := ui.Prompt{Label: label, IsConfirm: true}
+	_, err := prompt.Run()
+	return err == nil
 }
 
 func doctor(args []string) {
@@ -26,6 +49,7 @@ func doctor(args []string) {
 	if ii, err := items.All(); err != nil {
 		log.Fatal(err)
 	} else {
+		checkFilters(ii, args)
 		for _, item := range ii {
 			if nameMatch(item.Name(), args) {
 				itms = append(itms, item)
@@ -38,21 +62,195 @@ func doctor(args []string) {
 		if config.Bool("verbose") {
 			pri = fmt.Sprintf("(%3d) ", item.Priority())
 		}
+		src := sourceLabel(item)
+
+		var tainted bool
+		if tainter, ok := item.(items.Tainted); ok {
+			var err error
+			if tainted, err = tainter.IsTainted(); err != nil {
+				log.Errorf("%s%v%s IsTainted: %v", pri, item, src, err)
+				continue
+			}
+		}
+
+		installed, err := item.IsInstalled()
+		if err != nil {
+			log.Errorf("%s%v%s IsInstalled: %v", pri, item, src, err)
+			continue
+		}
+		missing := !installed
 
-		h := item.Handlers()
-		if h.Check == nil {
-			log.Errorf("%s%v has no check", pri, item)
+		if !doctorMatchesOnly(item, missing, tainted, pri, src) {
 			continue
 		}
 
-		if err := h.Check(); err != nil {
-			log.Badf("%s%v ... %v", pri, item, err)
-		} else {
-			log.Goodf("%s%v ... OK", pri, item)
+		switch {
+		case tainted:
+			log.Emphf("%s%v%s ... tainted (modified locally)", pri, item, src)
+			if fix {
+				fixTainted(item)
+			}
+		case missing:
+			log.Badf("%s%v%s ... missing", pri, item, src)
+			if fix {
+				fixMissing(item)
+			}
+		default:
+			if err := item.Check(); err != nil {
+				log.Badf("%s%v%s ... %v", pri, item, src, err)
+				if fix {
+					fixGeneric(item)
+				}
+			} else {
+				log.Goodf("%s%v%s ... OK", pri, item, src)
+			}
 		}
 	}
 }
 
+// sourceLabel returns a "[path/to/manifest.yaml]" suffix naming the
+// plugin manifest which declared item, when --verbose is set and the
+// item came from one.
+func sourceLabel(item items.Item) string {
+	if !config.Bool("verbose") {
+		return ""
+	}
+	if s, ok := item.(items.Sourced); ok {
+		if src := s.Source(); src != "" {
+			return fmt.Sprintf(" [%s]", src)
+		}
+	}
+	return ""
+}
+
+func fixMissing(item items.Item) {
+	if !confirm(fmt.Sprintf("Install %v?", item)) {
+		return
+	}
+	log.Emphf("Installing %v", item)
+	var noInstaller items.NoInstallerError
+	if err := item.Install(); errors.As(err, &noInstaller) {
+		log.Warnf("I don't know how to install %v", item)
+	} else if err != nil {
+		log.Error(err)
+	}
+}
+
+func fixTainted(item items.Item) {
+	label := fmt.Sprintf("Overwrite tainted %v?", item)
+	if reinstall {
+		label = fmt.Sprintf("Uninstall and reinstall tainted %v?", item)
+	}
+	if !confirm(label) {
+		return
+	}
+	if reinstall {
+		log.Emphf("Uninstalling %v", item)
+		var noUninstaller items.NoUninstallerError
+		if err := item.Uninstall(); err != nil && !errors.As(err, &noUninstaller) {
+			log.Error(err)
+			return
+		}
+	}
+	log.Emphf("Reinstalling %v", item)
+	var noInstaller items.NoInstallerError
+	if err := item.Install(); errors.As(err, &noInstaller) {
+		log.Warnf("I don't know how to reinstall %v", item)
+	} else if err != nil {
+		log.Error(err)
+	}
+}
+
+// fixGeneric handles a plain Check failure which isn't categorized as
+// missing or tainted, e.g. uncommitted dotfiles.
+func fixGeneric(item items.Item) {
+	if stasher, ok := item.(items.Stasher); ok {
+		fixStash(item, stasher)
+		return
+	}
+	if !reinstall {
+		return
+	}
+	if !confirm(fmt.Sprintf("Reinstall %v?", item)) {
+		return
+	}
+	log.Emphf("Uninstalling %v", item)
+	var noUninstaller items.NoUninstallerError
+	if err := item.Uninstall(); err != nil && !errors.As(err, &noUninstaller) {
+		log.Error(err)
+		return
+	}
+	log.Emphf("Reinstalling %v", item)
+	var noInstaller items.NoInstallerError
+	if err := item.Install(); errors.As(err, &noInstaller) {
+		log.Warnf("I don't know how to reinstall %v", item)
+	} else if err != nil {
+		log.Error(err)
+	}
+}
+
+func fixStash(item items.Item, stasher items.Stasher) {
+	diff, err := stasher.Diff()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if diff == "" {
+		return
+	}
+	fmt.Println(diff)
+	if !confirm(fmt.Sprintf("Stash changes in %v?", item)) {
+		return
+	}
+	log.Emphf("Stashing changes in %v", item)
+	if err := stasher.Stash(); err != nil {
+		log.Error(err)
+	}
+}
+
+// doctorMatchesOnly reports whether item, with the given missing/tainted
+// status, should be printed, according to the --only flag.
+func doctorMatchesOnly(item items.Item, missing, tainted bool, pri, src string) bool {
+	switch only {
+	case "":
+		return true
+	case "missing":
+		return missing
+	case "tainted":
+		return tainted
+	case "outdated":
+		return isOutdated(item, pri, src)
+	default:
+		log.Fatalf("unknown --only filter %q (want tainted, missing or outdated)", only)
+		return false
+	}
+}
+
+// isOutdated reports whether item has a newer version available
+// upstream than the one it currently has installed. Items which
+// aren't Versioned, or have no way to resolve an upstream version,
+// are never outdated.
+func isOutdated(item items.Item, pri, src string) bool {
+	v, ok := item.(items.Versioned)
+	if !ok {
+		return false
+	}
+	installed, err := v.Version()
+	if err != nil {
+		log.Errorf("%s%v%s Version: %v", pri, item, src, err)
+		return false
+	}
+	latest, err := v.LatestVersion()
+	var noVersion items.NoVersionError
+	if errors.As(err, &noVersion) {
+		return false
+	} else if err != nil {
+		log.Errorf("%s%v%s LatestVersion: %v", pri, item, src, err)
+		return false
+	}
+	return installed != latest
+}
+
 const doctorUsage = `usage: rogu doctor [options] [filters...]
 
 Diagnose your machine.
@@ -60,5 +258,12 @@ Diagnose your machine.
 If filters are given only items matching the filters will
 be checked.
 
+The --only flag filters by status, reporting only tainted,
+missing or outdated items.
+
+When --fix is given Rogu tries to fix any issues as soon as
+it is encountered, if he knows how. --reinstall additionally
+uninstalls a broken item before reinstalling it.
+
 Options:
 `
@@ -0,0 +1,328 @@
+package git
+
+import (
+	"fmt"
+	"github.com/go-git/go-billy/v5/osfs"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/mhmorgan/rogu/config"
+	"github.com/mhmorgan/rogu/sh"
+	"sort"
+	"strings"
+)
+
+// Location identifies a repository on disk: its .git directory and
+// the worktree it's checked out into. For a normal clone these are
+// "<root>/.git" and "<root>"; for a bare dotfiles-style clone they're
+// "<root>" and the user's home directory. CloneBare leaves WorkTree
+// empty, as it only creates the git directory.
+type Location struct {
+	GitDir   string
+	WorkTree string
+}
+
+// Backend implements the git operations Repo needs, so the CLI-based
+// implementation can be swapped for a pure-Go one on systems without
+// git installed, or to avoid subprocess overhead.
+type Backend interface {
+	Clone(url string, loc Location) error
+	CloneBare(url string, loc Location) error
+	LsFiles(loc Location) ([]string, error)
+	LsTree(loc Location, branch string) ([]string, error)
+	Status(loc Location) (modified []string, err error)
+	Commit(loc Location, message string, files []string) error
+	Pull(loc Location, branch string) error
+	Push(loc Location, branch string) error
+	Config(loc Location, key string) (string, error)
+	SetConfig(loc Location, key, value string) error
+}
+
+// defaultBackend picks the Backend according to Config.Rogu.GitBackend
+// (git_backend: cli|native), defaulting to the CLI-based one.
+func defaultBackend() Backend {
+	if config.Get().Rogu.GitBackend == "native" {
+		return nativeBackend{}
+	}
+	return cliBackend{}
+}
+
+func splitLines(s string) (lines []string) {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return
+}
+
+// cliBackend shells out to the git CLI, the way Repo always has.
+
+type cliBackend struct{}
+
+func (cliBackend) cmd(loc Location) string {
+	if loc.WorkTree != "" && loc.WorkTree != loc.GitDir {
+		return fmt.Sprintf("git --git-dir=%s --work-tree=%s ", loc.GitDir, loc.WorkTree)
+	}
+	return fmt.Sprintf("git -C %s ", loc.GitDir)
+}
+
+func (cliBackend) Clone(url string, loc Location) error {
+	return sh.Runf("git clone %s %s", url, loc.WorkTree)
+}
+
+func (cliBackend) CloneBare(url string, loc Location) error {
+	return sh.Runf("git clone --bare %s %s", url, loc.GitDir)
+}
+
+func (c cliBackend) LsFiles(loc Location) ([]string, error) {
+	out, _, err := sh.Execf("%sls-files", c.cmd(loc))
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %w", err)
+	}
+	return splitLines(out.String()), nil
+}
+
+func (c cliBackend) LsTree(loc Location, branch string) ([]string, error) {
+	out, _, err := sh.Execf("%sls-tree -r --name-only %s", c.cmd(loc), branch)
+	if err != nil {
+		return nil, fmt.Errorf("git ls-tree: %w", err)
+	}
+	return splitLines(out.String()), nil
+}
+
+func (c cliBackend) Status(loc Location) ([]string, error) {
+	out, _, err := sh.Execf("%sls-files --modified", c.cmd(loc))
+	if err != nil {
+		return nil, fmt.Errorf("git status: %w", err)
+	}
+	return splitLines(out.String()), nil
+}
+
+func (c cliBackend) Commit(loc Location, message string, files []string) error {
+	return sh.Runf("%scommit -m %q -- %s", c.cmd(loc), message, strings.Join(files, " "))
+}
+
+func (c cliBackend) Pull(loc Location, branch string) error {
+	return sh.Runf("%spull --rebase origin %s", c.cmd(loc), branch)
+}
+
+func (c cliBackend) Push(loc Location, branch string) error {
+	return sh.Runf("%spush origin %s", c.cmd(loc), branch)
+}
+
+func (c cliBackend) Config(loc Location, key string) (string, error) {
+	out, _, err := sh.Execf("%sconfig %s", c.cmd(loc), key)
+	if err != nil {
+		return "", fmt.Errorf("git config: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+func (c cliBackend) SetConfig(loc Location, key, value string) error {
+	return sh.Runf("%sconfig %s %s", c.cmd(loc), key, value)
+}
+
+// nativeBackend implements Backend with github.com/go-git/go-git/v5,
+// a pure-Go git implementation, for systems without git installed (or
+// to avoid subprocess overhead).
+
+type nativeBackend struct{}
+
+func (nativeBackend) Clone(url string, loc Location) error {
+	_, err := gogit.PlainClone(loc.WorkTree, false, &gogit.CloneOptions{URL: url})
+	return err
+}
+
+func (nativeBackend) CloneBare(url string, loc Location) error {
+	_, err := gogit.PlainClone(loc.GitDir, true, &gogit.CloneOptions{URL: url})
+	return err
+}
+
+// open opens loc, using go-git's separate storage/worktree filesystems
+// when WorkTree differs from GitDir, the layout bare dotfiles clones use.
+func (nativeBackend) open(loc Location) (*gogit.Repository, error) {
+	if loc.WorkTree == "" || loc.WorkTree == loc.GitDir {
+		return gogit.PlainOpen(loc.GitDir)
+	}
+	dot := osfs.New(loc.GitDir)
+	wt := osfs.New(loc.WorkTree)
+	storer := filesystem.NewStorage(dot, cache.NewObjectLRUDefault())
+	return gogit.Open(storer, wt)
+}
+
+func (n nativeBackend) LsFiles(loc Location) ([]string, error) {
+	repo, err := n.open(loc)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, len(idx.Entries))
+	for i, e := range idx.Entries {
+		files[i] = e.Name
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (n nativeBackend) LsTree(loc Location, branch string) ([]string, error) {
+	repo, err := n.open(loc)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = tree.Files().ForEach(func(f *object.File) error {
+		files = append(files, f.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (n nativeBackend) Status(loc Location) ([]string, error) {
+	repo, err := n.open(loc)
+	if err != nil {
+		return nil, err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for path, s := range status {
+		if s.Worktree == gogit.Untracked {
+			continue
+		}
+		if s.Worktree != gogit.Unmodified || s.Staging != gogit.Unmodified {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (n nativeBackend) Commit(loc Location, message string, files []string) error {
+	repo, err := n.open(loc)
+	if err != nil {
+		return err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if _, err := wt.Add(f); err != nil {
+			return err
+		}
+	}
+	_, err = wt.Commit(message, &gogit.CommitOptions{})
+	return err
+}
+
+func (n nativeBackend) Pull(loc Location, branch string) error {
+	repo, err := n.open(loc)
+	if err != nil {
+		return err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	err = wt.Pull(&gogit.PullOptions{
+		RemoteName:    "origin",
+		ReferenceName: plumbing.NewBranchReferenceName(branch),
+	})
+	if err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return err
+	}
+	return nil
+}
+
+func (n nativeBackend) Push(loc Location, branch string) error {
+	repo, err := n.open(loc)
+	if err != nil {
+		return err
+	}
+	err = repo.Push(&gogit.PushOptions{RemoteName: "origin"})
+	if err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return err
+	}
+	return nil
+}
+
+// splitConfigKey splits a dotted git config key ("branch.main.remote")
+// into its section, optional subsection, and option.
+func splitConfigKey(key string) (section, subsection, option string) {
+	parts := strings.Split(key, ".")
+	switch len(parts) {
+	case 2:
+		return parts[0], "", parts[1]
+	case 3:
+		return parts[0], parts[1], parts[2]
+	default:
+		return "", "", key
+	}
+}
+
+func (n nativeBackend) Config(loc Location, key string) (string, error) {
+	repo, err := n.open(loc)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+	section, subsection, option := splitConfigKey(key)
+	sec := cfg.Raw.Section(section)
+	if subsection != "" {
+		return sec.Subsection(subsection).Option(option), nil
+	}
+	return sec.Option(option), nil
+}
+
+func (n nativeBackend) SetConfig(loc Location, key, value string) error {
+	repo, err := n.open(loc)
+	if err != nil {
+		return err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	section, subsection, option := splitConfigKey(key)
+	sec := cfg.Raw.Section(section)
+	if subsection != "" {
+		sec.Subsection(subsection).SetOption(option, value)
+	} else {
+		sec.SetOption(option, value)
+	}
+	return repo.SetConfig(cfg)
+}
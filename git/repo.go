@@ -7,14 +7,17 @@ import (
 	"github.com/mhmorgan/rogu/sh"
 	"github.com/mhmorgan/rogu/utils"
 	"os"
+	"path/filepath"
 	"strings"
 	"text/template"
 )
 
 type Repo struct {
-	Root   string
-	Branch string
-	cmd    string
+	Root    string
+	Branch  string
+	cmd     string
+	backend Backend
+	loc     Location
 }
 
 func Open(root string, options ...Option) (r Repo, err error) {
@@ -22,9 +25,30 @@ func Open(root string, options ...Option) (r Repo, err error) {
 		return r, fmt.Errorf("%s: %w", root, os.ErrNotExist)
 	}
 	r = Repo{
-		Root:   root,
-		Branch: "main",
-		cmd:    "git",
+		Root:    root,
+		Branch:  "main",
+		cmd:     fmt.Sprintf("git -C %s", root),
+		backend: defaultBackend(),
+		loc:     Location{GitDir: filepath.Join(root, ".git"), WorkTree: root},
+	}
+	for _, option := range options {
+		option(&r)
+	}
+	return r, nil
+}
+
+// OpenBare opens a bare repository whose worktree lives elsewhere, the
+// layout dotfiles repos use (a bare git dir plus $HOME as the worktree).
+func OpenBare(gitDir, workTree string, options ...Option) (r Repo, err error) {
+	if !utils.PathExists(gitDir) {
+		return r, fmt.Errorf("%s: %w", gitDir, os.ErrNotExist)
+	}
+	r = Repo{
+		Root:    gitDir,
+		Branch:  "main",
+		cmd:     fmt.Sprintf("git --git-dir=%s --work-tree=%s ", gitDir, workTree),
+		backend: defaultBackend(),
+		loc:     Location{GitDir: gitDir, WorkTree: workTree},
 	}
 	for _, option := range options {
 		option(&r)
@@ -35,86 +59,108 @@ func Open(root string, options ...Option) (r Repo, err error) {
 func DotfilesRepo() (Repo, error) {
 	cfg := config.Get()
 	root := os.ExpandEnv("$HOME/.dotfiles")
-	cmd := fmt.Sprintf("git --git-dir=%s --work-tree=%s ", root, utils.Home())
-	return Open(root, WithBranch(cfg.Dotfiles.Branch), WithCmd(cmd))
+	return OpenBare(root, utils.Home(), WithBranch(cfg.Dotfiles.Branch))
 }
 
 func Clone(repo, root string) error {
-	return sh.Runf("git clone %s %s", repo, root)
+	loc := Location{GitDir: filepath.Join(root, ".git"), WorkTree: root}
+	return defaultBackend().Clone(withAuth(repo), loc)
 }
 
 func CloneBare(repo, root string) error {
-	return sh.Runf("git clone --bare %s %s", repo, root)
+	loc := Location{GitDir: root}
+	return defaultBackend().CloneBare(withAuth(repo), loc)
+}
+
+// InitTemplate initializes a new git repository in the current
+// directory using tmplDir as a --template directory (see
+// git-init(1)), so hooks/config shipped with a "rogu init" template
+// carry over into the repo it creates.
+func InitTemplate(tmplDir string) error {
+	return sh.Runf("git init --template=%s .", tmplDir)
 }
 
-// Files returns a list of files tracked by the repository.
-//
-// For this to work you must be in the work-tree of the
-// repository.
-func (r Repo) Files() (files []string, err error) {
-	out, _, err := sh.Execf("%s ls-files", r.cmd)
+// LsRemote returns the commit SHA at the tip of branch on repo's
+// remote, without needing a local clone.
+func LsRemote(repo, branch string) (string, error) {
+	out, _, err := sh.Execf("git ls-remote %s refs/heads/%s", withAuth(repo), branch)
 	if err != nil {
-		return nil, fmt.Errorf("git ls-files: %w", err)
+		return "", fmt.Errorf("git ls-remote: %w", err)
 	}
-
-	for _, file := range strings.Split(out.String(), "\n") {
-		file = strings.TrimSpace(file)
-		if file != "" {
-			files = append(files, file)
-		}
+	fields := strings.Fields(out.String())
+	if len(fields) == 0 {
+		return "", fmt.Errorf("git ls-remote: no ref %q found on %s", branch, repo)
 	}
-	return
+	return fields[0], nil
+}
+
+// Files returns a list of files tracked by the repository's worktree.
+func (r Repo) Files() ([]string, error) {
+	return r.backend.LsFiles(r.loc)
+}
+
+// Tree returns a list of files tracked at the tip of branch, without
+// requiring a checked out worktree. Used to find which files a bare
+// clone is about to check out, before it has one.
+func (r Repo) Tree(branch string) ([]string, error) {
+	return r.backend.LsTree(r.loc, branch)
 }
 
 func (r Repo) IsDirty() (bool, error) {
-	out, _, err := sh.Execf("%s status --short", r.cmd)
+	files, err := r.ModifiedFiles()
 	if err != nil {
-		return false, fmt.Errorf("git status: %w", err)
+		return false, err
 	}
-	return out.Len() > 0, nil
+	return len(files) > 0, nil
 }
 
 // ModifiedFiles returns a list of modified and added files.
-func (r Repo) ModifiedFiles() (files []string, err error) {
-	out, _, err := sh.Execf("%s ls-files --modified", r.cmd)
+func (r Repo) ModifiedFiles() ([]string, error) {
+	return r.backend.Status(r.loc)
+}
+
+func (r Repo) Sync() error {
+	files, err := r.ModifiedFiles()
 	if err != nil {
-		return nil, fmt.Errorf("git ls-files: %w", err)
+		return err
 	}
-	for _, line := range strings.Split(out.String(), "\n") {
-		if line == "" {
-			continue
+	if len(files) > 0 {
+		if err := r.backend.Commit(r.loc, fmt.Sprintf("Updating %s", strings.Join(files, " ")), files); err != nil {
+			return err
 		}
-		files = append(files, line)
 	}
-	return
-}
-
-func (r Repo) Sync() error {
-	var commit, push string
-	if files, err := r.ModifiedFiles(); err != nil {
+	if err := r.backend.Pull(r.loc, r.Branch); err != nil {
 		return err
-	} else if len(files) > 0 {
-		s := strings.Join(files, " ")
-		commit = fmt.Sprintf("%s commit -m 'Updating %s' -- %s ; ", r.cmd, s, s)
-		push = fmt.Sprintf("%s push origin %s ; ", r.cmd, r.Branch)
 	}
-
-	pull := fmt.Sprintf("%s pull --rebase origin %s ; ", r.cmd, r.Branch)
-	return sh.Runf("%s%s%s", commit, pull, push)
+	if len(files) > 0 {
+		if err := r.backend.Push(r.loc, r.Branch); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (r Repo) Config(key string) (string, error) {
-	out, _, err := r.Exec("config %s", key)
+// Head returns the commit SHA currently checked out.
+func (r Repo) Head() (string, error) {
+	out, _, err := r.Exec("rev-parse HEAD")
 	if err != nil {
-		return "", fmt.Errorf("git config: %w", err)
+		return "", fmt.Errorf("git rev-parse HEAD: %w", err)
 	}
-	return out.String(), nil
+	return strings.TrimSpace(out.String()), nil
+}
+
+func (r Repo) Config(key string) (string, error) {
+	return r.backend.Config(r.loc, key)
 }
 
 func (r Repo) SetConfig(key, value string) error {
-	return r.Run("config %s %s", key, value)
+	return r.backend.SetConfig(r.loc, key, value)
 }
 
+// Run and Exec shell out to the git CLI directly for operations
+// outside Backend's scope (e.g. checkout, diff, stash), so they're
+// unaffected by the git_backend setting.
+
 func (r Repo) Run(s string, args ...any) error {
 	s, err := r.fmt(s)
 	if err != nil {
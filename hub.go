@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/mhmorgan/rogu/hub"
+	"github.com/mhmorgan/rogu/items"
+	log "github.com/mhmorgan/termlog"
+	"gopkg.in/yaml.v3"
+	"os"
+)
+
+func init() {
+	commands["search"] = &command{searchFlags, search}
+	commands["show"] = &command{showFlags, show}
+	commands["install"] = &command{installFlags, install}
+}
+
+func searchFlags(flags *flag.FlagSet) {
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, searchUsage)
+		flags.PrintDefaults()
+		os.Exit(2)
+	}
+}
+
+func search(args []string) {
+	if len(args) != 1 {
+		log.Fatal("expected exactly one argument: a substring to search for")
+	}
+	idx, err := hub.Fetch()
+	if err != nil {
+		log.Fatal(err)
+	}
+	names := hub.Search(idx, args[0])
+	if len(names) == 0 {
+		log.Badf("no hub items match %q", args[0])
+		return
+	}
+	for _, name := range names {
+		fmt.Printf("%s (%s)\n", name, idx[name].Type)
+	}
+}
+
+func showFlags(flags *flag.FlagSet) {
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, showUsage)
+		flags.PrintDefaults()
+		os.Exit(2)
+	}
+}
+
+func show(args []string) {
+	if len(args) != 1 {
+		log.Fatal("expected exactly one argument: the name of a hub entry")
+	}
+	idx, err := hub.Fetch()
+	if err != nil {
+		log.Fatal(err)
+	}
+	entry, ok := idx[args[0]]
+	if !ok {
+		log.Fatalf("no hub entry named %q", args[0])
+	}
+	b, err := yaml.Marshal(map[string]hub.Entry{args[0]: entry})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(string(b))
+}
+
+func installFlags(flags *flag.FlagSet) {
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, installUsage)
+		flags.PrintDefaults()
+		os.Exit(2)
+	}
+}
+
+func install(args []string) {
+	if len(args) != 1 {
+		log.Fatal("expected exactly one argument: the name of a hub entry")
+	}
+	name := args[0]
+
+	idx, err := hub.Fetch()
+	if err != nil {
+		log.Fatal(err)
+	}
+	entry, ok := idx[name]
+	if !ok {
+		log.Fatalf("no hub entry named %q", name)
+	}
+
+	if err := hub.Add(name, entry); err != nil {
+		log.Fatal(err)
+	}
+
+	itms, err := items.All()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, item := range itms {
+		if item.Name() == name {
+			if err := syncItem(item); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+	log.Fatalf("%s was added to %s but couldn't be found among Rogu's items", name, hub.ManifestPath())
+}
+
+const searchUsage = `usage: rogu search <substr>
+
+Search the hub index for items whose name matches substr.
+
+Options:
+`
+
+const showUsage = `usage: rogu show <name>
+
+Show a hub index entry.
+
+Options:
+`
+
+const installUsage = `usage: rogu install <name>
+
+Install fetches the hub index, looks up name, and adds it to
+the managed ~/.rogu.d/hub.yaml plugin manifest, then installs
+it the same way sync would.
+
+Options:
+`
@@ -0,0 +1,204 @@
+// Package hub implements a lightweight remote item catalog, analogous
+// to CrowdSec's cwhub: a YAML index, published at a well-known URL,
+// listing File/Script/Template items a user can discover and install
+// by name instead of hand-writing them into ~/.rogu. The index itself
+// is not signature-checked; a File entry's sha256 is verified when the
+// file is installed.
+package hub
+
+import (
+	"fmt"
+	"github.com/mhmorgan/rogu/config"
+	"github.com/mhmorgan/rogu/items"
+	"github.com/mhmorgan/rogu/sh"
+	"github.com/mhmorgan/rogu/utils"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultIndexUrl is used when hub.index_url isn't set in the config.
+const DefaultIndexUrl = "https://raw.githubusercontent.com/MHmorgan/rogu-hub/main/index.yaml"
+
+// Entry describes a single item published in the hub index. It mirrors
+// the shape of config.FileEntry/ScriptEntry/TemplateEntry so a
+// selected entry can be converted straight into one.
+type Entry struct {
+	Type         string   `yaml:"type"` // file, script or template
+	Url          string   `yaml:"url"`
+	Dst          string   `yaml:"dst,omitempty"`    // File items
+	Mode         int      `yaml:"mode,omitempty"`   // File items
+	Branch       string   `yaml:"branch,omitempty"` // Template items
+	Sha256       string   `yaml:"sha256,omitempty"` // File items, verified before installing
+	Version      string   `yaml:"version,omitempty"`
+	Priority     int      `yaml:"priority,omitempty"`
+	Dependencies []string `yaml:"dependencies,omitempty"`
+}
+
+// Index is the hub catalog, keyed by item name.
+type Index map[string]Entry
+
+func indexUrl() string {
+	if url := config.Get().Hub.IndexUrl; url != "" {
+		return url
+	}
+	return DefaultIndexUrl
+}
+
+func cacheDir() string {
+	return filepath.Join(utils.Home(), ".cache", "rogu", "hub")
+}
+
+func indexPath() string {
+	return filepath.Join(cacheDir(), "index.yaml")
+}
+
+func etagPath() string {
+	return filepath.Join(cacheDir(), "index.etag")
+}
+
+// Fetch returns the hub index, re-downloading it only if the cached
+// copy is stale according to the remote's ETag (or Last-Modified, if
+// no ETag is sent). If the remote sends neither, it's re-downloaded
+// every time.
+func Fetch() (Index, error) {
+	url := indexUrl()
+
+	_, ok, headers, err := utils.UrlExists(url)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("hub index %q is unreachable", url)
+	}
+
+	tag := headers.Get("Etag")
+	if tag == "" {
+		tag = headers.Get("Last-Modified")
+	}
+
+	if tag == "" || tag != cachedTag() || !utils.PathExists(indexPath()) {
+		if err := download(url); err != nil {
+			return nil, err
+		}
+		if tag != "" {
+			if err := os.WriteFile(etagPath(), []byte(tag), 0644); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return load(indexPath())
+}
+
+func cachedTag() string {
+	b, err := os.ReadFile(etagPath())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+func download(url string) error {
+	if err := os.MkdirAll(cacheDir(), 0755); err != nil {
+		return err
+	}
+	return sh.Runf("curl -sSLf %s -o %s", url, indexPath())
+}
+
+func load(path string) (Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	idx := make(Index)
+	if err := yaml.NewDecoder(f).Decode(&idx); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// Search returns the names of the index entries containing substr,
+// sorted alphabetically.
+func Search(idx Index, substr string) []string {
+	substr = strings.ToLower(substr)
+	var names []string
+	for name := range idx {
+		if strings.Contains(strings.ToLower(name), substr) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ManifestPath is the plugin manifest Rogu manages for hub-installed
+// items, so they're picked up by items.All() the same way any other
+// plugin manifest is.
+func ManifestPath() string {
+	return filepath.Join(items.PluginDir(), "hub.yaml")
+}
+
+// Add writes entry into the managed hub manifest under name, creating
+// or extending it as needed.
+func Add(name string, entry Entry) error {
+	path := ManifestPath()
+
+	var cfg config.Config
+	if utils.PathExists(path) {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		err = yaml.NewDecoder(f).Decode(&cfg)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	switch entry.Type {
+	case "file":
+		if cfg.Files == nil {
+			cfg.Files = make(map[string]config.FileEntry)
+		}
+		cfg.Files[name] = config.FileEntry{
+			Priority:     entry.Priority,
+			Url:          entry.Url,
+			Dst:          entry.Dst,
+			Mode:         entry.Mode,
+			Dependencies: entry.Dependencies,
+			Sha256:       entry.Sha256,
+		}
+	case "script":
+		// Script items run arbitrary shell commands from the index,
+		// so they're deliberately not auto-installable; show them
+		// with "rogu show" and let the user copy them in by hand.
+		return fmt.Errorf("hub: %s is a script item, install it by hand after reviewing it with \"rogu show\"", name)
+	case "template":
+		if cfg.Templates == nil {
+			cfg.Templates = make(map[string]config.TemplateEntry)
+		}
+		cfg.Templates[name] = config.TemplateEntry{
+			Priority:     entry.Priority,
+			Url:          entry.Url,
+			Branch:       entry.Branch,
+			Dependencies: entry.Dependencies,
+		}
+	default:
+		return fmt.Errorf("hub: %s: unknown item type %q", name, entry.Type)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return yaml.NewEncoder(f).Encode(&cfg)
+}
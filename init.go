@@ -57,7 +57,7 @@ func init_(args []string) {
 	// Get template directory
 	var tmplDir string
 	for _, itm := range itms {
-		tmpl := itm.(items.Template)
+		tmpl := itm.(*items.Template)
 		if tmpl.Name() == args[0] {
 			tmplDir = tmpl.Root()
 			break
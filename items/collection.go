@@ -0,0 +1,144 @@
+package items
+
+import (
+	"fmt"
+	"github.com/mhmorgan/rogu/config"
+)
+
+func init() {
+	itemsFactories = append(itemsFactories, func() ([]Item, error) {
+		return collectionsFrom(config.Get(), "")
+	})
+}
+
+// collectionsFrom builds the Collection items declared in cfg. source
+// records which file declared them (empty for the main ~/.rogu
+// config).
+func collectionsFrom(cfg *config.Config, source string) (items []Item, err error) {
+	for name, val := range cfg.Collections {
+		items = append(items, &Collection{
+			name:         name,
+			priority:     val.Priority,
+			members:      val.Items,
+			dependencies: val.Dependencies,
+			source:       source,
+		})
+	}
+	return
+}
+
+// Collection item
+//
+// A Collection bundles a list of member item names, similar to how
+// CrowdSec's cwhub collections group parsers/scenarios/postoverflows
+// and pull them in transitively.
+//
+// Its members are also returned by All() as items in their own right,
+// ahead of the collection in dependency order (see Dependencies), so
+// sync/doctor already install and update them directly: Install and
+// Update are no-ops here to avoid running each member a second time.
+// Uninstall still delegates to the members via forEach, since removing
+// a collection by name is the only way to reach its members for that
+// action.
+type Collection struct {
+	name         string
+	priority     int
+	members      []string
+	dependencies []string
+	source       string
+}
+
+func (c *Collection) String() string {
+	return c.name
+}
+
+func (c *Collection) Name() string {
+	return c.name
+}
+
+func (c *Collection) Priority() int {
+	return c.priority
+}
+
+func (c *Collection) Type() ItemType {
+	return CollectionItem
+}
+
+// Dependencies includes both the collection's own dependencies and
+// its members, so All() always places the members ahead of it.
+func (c *Collection) Dependencies() []string {
+	deps := append([]string{}, c.dependencies...)
+	return append(deps, c.members...)
+}
+
+func (c *Collection) Source() string {
+	return c.source
+}
+
+func (c *Collection) Check() error {
+	return c.forEach(func(item Item) error { return item.Check() })
+}
+
+// Install is a no-op: All() already lists c's members as items in
+// their own right, ahead of c in dependency order, so sync installs
+// them directly without going through c.
+func (c *Collection) Install() error {
+	return NoInstallerError{c.name}
+}
+
+// Update is a no-op: All() already lists c's members as items in
+// their own right, ahead of c in dependency order, so sync updates
+// them directly without going through c.
+func (c *Collection) Update() error {
+	return NoUpdaterError{c.name}
+}
+
+func (c *Collection) Uninstall() error {
+	return c.forEach(func(item Item) error { return item.Uninstall() })
+}
+
+func (c *Collection) IsInstalled() (bool, error) {
+	for _, name := range c.orderedMembers() {
+		item, ok := registry[name]
+		if !ok {
+			return false, fmt.Errorf("%s: unknown member %q", c.name, name)
+		}
+		if installed, err := item.IsInstalled(); err != nil || !installed {
+			return installed, err
+		}
+	}
+	return true, nil
+}
+
+// forEach runs action for every member, in dependency order. A member
+// which doesn't support the action (e.g. a script with no uninstall
+// code) is skipped rather than failing the whole collection.
+func (c *Collection) forEach(action func(Item) error) error {
+	for _, name := range c.orderedMembers() {
+		item, ok := registry[name]
+		if !ok {
+			return fmt.Errorf("%s: unknown member %q", c.name, name)
+		}
+		if err := action(item); err != nil && !unsupported(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderedMembers returns the collection's members in the same
+// dependency order All() produced for the full item list.
+func (c Collection) orderedMembers() []string {
+	wanted := make(map[string]bool, len(c.members))
+	for _, m := range c.members {
+		wanted[m] = true
+	}
+
+	names := make([]string, 0, len(c.members))
+	for _, name := range order {
+		if wanted[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"github.com/mhmorgan/rogu/config"
 	"github.com/mhmorgan/rogu/git"
+	"github.com/mhmorgan/rogu/sh"
 	"github.com/mhmorgan/rogu/utils"
 	"os"
+	"path/filepath"
 )
 
 const (
@@ -21,9 +23,9 @@ func init() {
 
 func DotfilesItem() Item {
 	cfg := config.Get()
-	return dotfilesItem{
+	return &dotfilesItem{
 		root:   os.ExpandEnv("$HOME/.dotfiles"),
-		url:    cfg.Dotfiles.Repo,
+		url:    cfg.Dotfiles.Url,
 		branch: cfg.Dotfiles.Branch,
 	}
 }
@@ -34,108 +36,186 @@ type dotfilesItem struct {
 	branch string
 }
 
-func (d dotfilesItem) String() string {
+func (d *dotfilesItem) String() string {
 	return dotfilesItemName
 }
 
-func (d dotfilesItem) Name() string {
+func (d *dotfilesItem) Name() string {
 	return dotfilesItemName
 }
 
-func (d dotfilesItem) Priority() int {
+func (d *dotfilesItem) Priority() int {
 	return 60
 }
 
-func (d dotfilesItem) Type() ItemType {
+func (d *dotfilesItem) Type() ItemType {
 	return DotfileItem
 }
 
-func (d dotfilesItem) Handlers() ItemHandlers {
-	return ItemHandlers{
-		Check:       d.checker(),
-		IsInstalled: d.isInstalled(),
-		Install:     d.installer(),
-		Update:      d.updater(),
-	}
+func (d *dotfilesItem) Dependencies() []string {
+	return nil
 }
 
-func (d dotfilesItem) checker() Fn {
-	return func() error {
-		repo, err := git.DotfilesRepo()
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				return errors.New("dotfiles repository not installed")
-			}
-			return err
+func (d *dotfilesItem) Check() error {
+	repo, err := git.DotfilesRepo()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return errors.New("dotfiles repository not installed")
 		}
-		utils.CdHome()
-
-		files, err := repo.Files()
-		if err != nil {
-			return err
+		return err
+	}
+	files, err := repo.Files()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if !utils.PathExists(filepath.Join(utils.Home(), file)) {
+			return fmt.Errorf("dotfile %q is missing", file)
 		}
-		for _, file := range files {
-			if !utils.PathExists(file) {
-				return fmt.Errorf("dotfile %q is missing", file)
+	}
+
+	if files, err := repo.ModifiedFiles(); err != nil {
+		return err
+	} else if len(files) > 0 {
+		return errors.New("dotfiles repository has uncommitted changes")
+	}
+	return nil
+}
+
+func (d *dotfilesItem) IsInstalled() (bool, error) {
+	return utils.PathExists(d.root), nil
+}
+
+func (d *dotfilesItem) Install() error {
+	if err := git.CloneBare(d.url, d.root); err != nil {
+		return err
+	}
+	repo, err := git.DotfilesRepo()
+	if err != nil {
+		return err
+	}
+	// Backup existing dotfiles. The worktree isn't checked out
+	// yet, so list the branch's tree directly instead of the
+	// (still empty) index.
+	files, err := repo.Tree(d.branch)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if utils.PathExists(filepath.Join(utils.Home(), file)) {
+			if err = utils.Backup(utils.Home(), file); err != nil {
+				return err
 			}
 		}
+	}
 
-		if files, err := repo.ModifiedFiles(); err != nil {
-			return err
-		} else if len(files) > 0 {
-			return errors.New("dotfiles repository has uncommitted changes")
-		}
-		return nil
+	if err := repo.Run("checkout %s", d.branch); err != nil {
+		return err
 	}
+	_ = repo.SetConfig("advice.addIgnoredFile", "false")
+	_ = repo.SetConfig(fmt.Sprintf("branch.%s.remote", d.branch), "origin")
+	_ = repo.SetConfig(fmt.Sprintf("branch.%s.merge", d.branch), fmt.Sprintf("refs/heads/%s", d.branch))
+	return nil
 }
 
-func (d dotfilesItem) installer() Fn {
-	return func() error {
-		if err := git.CloneBare(d.url, d.root); err != nil {
-			return err
-		}
-		repo, err := git.DotfilesRepo()
-		if err != nil {
-			return err
+func (d *dotfilesItem) Update() error {
+	if config.Get().Dotfiles.Atomic {
+		return d.updateWithWorktree()
+	}
+	repo, err := git.DotfilesRepo()
+	if err != nil {
+		return err
+	}
+	return repo.Sync()
+}
+
+// updateWithWorktree stages the update in a disposable `git worktree`
+// and only copies the result into $HOME once it applies cleanly, so a
+// conflicting pull can't leave $HOME half-updated: a failure just
+// leaves the scratch tree around to inspect instead of a half-synced
+// home directory.
+func (d *dotfilesItem) updateWithWorktree() (err error) {
+	repo, err := git.DotfilesRepo()
+	if err != nil {
+		return err
+	}
+
+	parent, err := os.MkdirTemp("", "rogu-dotfiles-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			os.RemoveAll(parent)
 		}
-		utils.CdHome()
+	}()
+	scratch := filepath.Join(parent, "worktree")
 
-		// Backup existing dotfiles
-		files, err := repo.Files()
+	if err := repo.Run("worktree add %s %s", scratch, d.branch); err != nil {
+		return err
+	}
+	defer func() {
 		if err != nil {
-			return err
+			err = fmt.Errorf("%w (scratch worktree kept at %s)", err, scratch)
+			return
 		}
-		for _, file := range files {
-			if utils.PathExists(file) {
-				if err = utils.Backup(file); err != nil {
-					return err
-				}
-			}
-		}
-
-		if err := repo.Run("checkout %s", d.branch); err != nil {
+		_ = repo.Run("worktree remove --force %s", scratch)
+		_ = repo.Run("worktree prune")
+	}()
+
+	// worktree add checks out the last commit, which knows nothing
+	// about uncommitted edits already sitting in $HOME. Copy the
+	// tracked files' current content into the scratch tree so Sync
+	// sees (and commits) the same changes it would against $HOME
+	// directly.
+	home := utils.Home()
+	files, err := repo.Files()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := sh.Runf("cp %s %s", filepath.Join(home, file), filepath.Join(scratch, file)); err != nil {
 			return err
 		}
-		_ = repo.SetConfig("advice.addIgnoredFile", "false")
-		_ = repo.SetConfig("branch.{{ .Branch }}.remote", "origin")
-		_ = repo.SetConfig("branch.{{ .Branch }}.merge", "refs/heads/{{ .Branch }}")
-		return nil
 	}
+
+	scratchRepo, err := git.OpenBare(d.root, scratch, git.WithBranch(d.branch))
+	if err != nil {
+		return err
+	}
+	if err := scratchRepo.Sync(); err != nil {
+		return err
+	}
+
+	// Exclude .git: it's a gitlink file pointing at the scratch
+	// worktree's metadata, and copying it into $HOME would rebind
+	// $HOME to a stale worktree instead of the real dotfiles repo.
+	return sh.Runf("rsync -a --exclude=.git %s/ %s/", scratch, home)
 }
 
-func (d dotfilesItem) updater() Fn {
-	return func() error {
-		repo, err := git.DotfilesRepo()
-		if err != nil {
-			return err
-		}
-		utils.CdHome()
-		return repo.Sync()
+func (d *dotfilesItem) Uninstall() error {
+	return NoUninstallerError{dotfilesItemName}
+}
+
+// Diff returns the uncommitted changes in the dotfiles repository.
+func (d *dotfilesItem) Diff() (string, error) {
+	repo, err := git.DotfilesRepo()
+	if err != nil {
+		return "", err
+	}
+
+	out, _, err := repo.Exec("diff")
+	if err != nil {
+		return "", fmt.Errorf("git diff: %w", err)
 	}
+	return out.String(), nil
 }
 
-func (d dotfilesItem) isInstalled() BoolFn {
-	return func() (bool, error) {
-		return utils.PathExists(d.root), nil
+// Stash stashes the uncommitted changes in the dotfiles repository.
+func (d *dotfilesItem) Stash() error {
+	repo, err := git.DotfilesRepo()
+	if err != nil {
+		return err
 	}
+	return repo.Run("stash")
 }
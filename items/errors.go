@@ -25,3 +25,44 @@ type NoInstalledCheckError struct {
 func (e NoInstalledCheckError) Error() string {
 	return fmt.Sprintf("%s has no installed checker", e.Name)
 }
+
+type NoUpdaterError struct {
+	Name string
+}
+
+func (e NoUpdaterError) Error() string {
+	return fmt.Sprintf("%s has no updater", e.Name)
+}
+
+type NoUninstallerError struct {
+	Name string
+}
+
+func (e NoUninstallerError) Error() string {
+	return fmt.Sprintf("%s has no uninstaller", e.Name)
+}
+
+// NoVersionError is returned by Versioned.LatestVersion when an item
+// has no way to resolve an upstream version (e.g. a File with neither
+// a version_url nor a recognizable GitHub releases URL). outdated and
+// upgrade use it to skip such items instead of reporting an error.
+type NoVersionError struct {
+	Name string
+}
+
+func (e NoVersionError) Error() string {
+	return fmt.Sprintf("%s has no version resolver", e.Name)
+}
+
+// unsupported reports whether err signals that an item simply doesn't
+// support the requested action (e.g. a script with no uninstall code),
+// rather than that the action failed. Collection uses it to skip such
+// members instead of failing the whole operation.
+func unsupported(err error) bool {
+	switch err.(type) {
+	case NoInstallerError, NoUpdaterError, NoUninstallerError, NoInstalledCheckError:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,59 +1,85 @@
 package items
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/mhmorgan/rogu/config"
 	"github.com/mhmorgan/rogu/sh"
+	"github.com/mhmorgan/rogu/state"
 	"github.com/mhmorgan/rogu/utils"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 )
 
+// githubReleaseURL matches a GitHub releases URL, e.g.
+// https://github.com/OWNER/REPO/releases/download/vX.Y.Z/asset, so
+// LatestVersion can ask the GitHub API for the newest release tag
+// instead of trusting whatever's embedded in the File's url.
+var githubReleaseURL = regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)/releases/`)
+
 func init() {
-	itemsFactories = append(itemsFactories, fileItems)
+	itemsFactories = append(itemsFactories, func() ([]Item, error) {
+		return filesFrom(config.Get(), "")
+	})
 }
 
-func fileItems() (items []Item, err error) {
-	cfg := config.Get()
-
+// filesFrom builds the File items declared in cfg. source records
+// which file declared them (empty for the main ~/.rogu config).
+func filesFrom(cfg *config.Config, source string) (items []Item, err error) {
 	for name, val := range cfg.Files {
 		item := File{
-			name:     name,
-			priority: val.Priority,
-			url:      val.Url,
-			dst:      val.Dst,
-			mode:     val.Mode,
+			name:           name,
+			priority:       val.Priority,
+			url:            val.Url,
+			dst:            val.Dst,
+			mode:           val.Mode,
+			dependencies:   val.Deps(),
+			source:         source,
+			expectedSha256: val.Sha256,
+			versionUrl:     val.VersionUrl,
+			hooks: hooks{
+				preInstall:    val.PreInstall,
+				postInstall:   val.PostInstall,
+				preUpdate:     val.PreUpdate,
+				postUpdate:    val.PostUpdate,
+				preUninstall:  val.PreUninstall,
+				postUninstall: val.PostUninstall,
+			},
 		}
 		if item.mode == 0 {
 			item.mode = config.DefaultMode
 		}
-		items = append(items, item)
+		items = append(items, &item)
 	}
 	return
 }
 
-func fileChecker(name, url, dst string) func() error {
+func fileCheck(name, url, dst string) error {
 	home := utils.Home()
 	dst = strings.Replace(dst, "~", home, 1)
 
-	return func() error {
-		if resp, _, err := utils.UrlExists(url); err != nil {
-			return err
-		} else if resp != 200 {
-			return fmt.Errorf("%s: bad URL (response %d): %q", name, resp, url)
-		}
+	if resp, _, _, err := utils.UrlExists(url); err != nil {
+		return err
+	} else if resp != 200 {
+		return fmt.Errorf("%s: bad URL (response %d): %q", name, resp, url)
+	}
 
-		if ok := utils.PathExists(dst); !ok {
-			return fmt.Errorf("%s: %w", dst, os.ErrNotExist)
-		}
-		return nil
+	if ok := utils.PathExists(dst); !ok {
+		return fmt.Errorf("%s: %w", dst, os.ErrNotExist)
 	}
+	return nil
 }
 
-func fileInstaller(srcUrl, dst string, mode int) func() error {
+func fileInstaller(name, srcUrl, dst string, mode int, expectedSha256 string, versionFn func() (string, error)) func() error {
 	tmpDir := os.ExpandEnv("$HOME/.cache/rogu")
 	tmpFile := filepath.Join("~/.cache/rogu", filepath.Base(dst))
+	tmpFileExpanded := filepath.Join(tmpDir, filepath.Base(dst))
+
+	home := utils.Home()
+	hashDst := strings.Replace(dst, "~", home, 1)
 
 	// Handle compression, based on file extension
 	var curl string
@@ -68,7 +94,7 @@ func fileInstaller(srcUrl, dst string, mode int) func() error {
 	mv := fmt.Sprintf("mv %s %s ; ", tmpFile, dst)
 	chmod := fmt.Sprintf("chmod 0%o %s ; ", mode, dst)
 	return func() error {
-		resp, ok, err := utils.UrlExists(srcUrl)
+		resp, ok, _, err := utils.UrlExists(srcUrl)
 		if err != nil {
 			return err
 		}
@@ -80,52 +106,197 @@ func fileInstaller(srcUrl, dst string, mode int) func() error {
 				return err
 			}
 		}
-		return sh.Runf("%s%s%s", curl, mv, chmod)
+		if err := sh.Runf("%s", curl); err != nil {
+			return err
+		}
+
+		if expectedSha256 != "" {
+			sum, err := utils.Sha256(tmpFileExpanded)
+			if err != nil {
+				return err
+			}
+			if sum != expectedSha256 {
+				return fmt.Errorf("%s: downloaded artifact sha256 %s does not match expected %s", name, sum, expectedSha256)
+			}
+		}
+
+		if err := sh.Runf("%s%s", mv, chmod); err != nil {
+			return err
+		}
+
+		sum, err := utils.Sha256(hashDst)
+		if err != nil {
+			return err
+		}
+		entry := state.Entry{
+			Sha256:      sum,
+			Url:         srcUrl,
+			Mode:        mode,
+			InstalledAt: time.Now(),
+		}
+		if versionFn != nil {
+			if entry.Version, err = versionFn(); err != nil {
+				return err
+			}
+		}
+		return state.Set(name, entry)
+	}
+}
+
+// fileVersionResolver returns the function which resolves a File
+// item's latest upstream version, or nil if neither a version_url nor
+// a recognizable GitHub releases URL is available.
+func fileVersionResolver(name, url, versionUrl string) func() (string, error) {
+	switch {
+	case versionUrl != "":
+		return func() (string, error) {
+			b, code, err := sh.Execf("curl -sSLf %s", versionUrl)
+			if err != nil {
+				return "", err
+			}
+			if code != 0 {
+				return "", fmt.Errorf("%s: version_url returned exit code %d", name, code)
+			}
+			return strings.TrimSpace(b.String()), nil
+		}
+	case githubReleaseURL.MatchString(url):
+		m := githubReleaseURL.FindStringSubmatch(url)
+		owner, repo := m[1], m[2]
+		return func() (string, error) {
+			return latestGithubRelease(owner, repo)
+		}
+	default:
+		return nil
+	}
+}
+
+// latestGithubRelease returns the tag name of owner/repo's latest
+// GitHub release.
+func latestGithubRelease(owner, repo string) (string, error) {
+	api := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", owner, repo)
+	b, code, err := sh.Execf("curl -sSLf %s", api)
+	if err != nil {
+		return "", err
+	}
+	if code != 0 {
+		return "", fmt.Errorf("%s: response code %d", api, code)
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.Unmarshal(b.Bytes(), &release); err != nil {
+		return "", fmt.Errorf("%s: %w", api, err)
+	}
+	return release.TagName, nil
+}
+
+// fileTaintChecker returns a function reporting whether the file at
+// dst has been modified locally since it was installed, by comparing
+// its current content hash against the one recorded at install time.
+func fileTaintChecker(name, dst string) func() (bool, error) {
+	home := utils.Home()
+	dst = strings.Replace(dst, "~", home, 1)
+
+	return func() (bool, error) {
+		entry, ok, err := state.Get(name)
+		if err != nil {
+			return false, err
+		}
+		if !ok || !utils.PathExists(dst) {
+			return false, nil
+		}
+		sum, err := utils.Sha256(dst)
+		if err != nil {
+			return false, err
+		}
+		return sum != entry.Sha256, nil
 	}
 }
 
 // File item
 
 type File struct {
-	name     string
-	priority int
-	url      string
-	dst      string
-	mode     int
+	name           string
+	priority       int
+	url            string
+	dst            string
+	mode           int
+	dependencies   []string
+	source         string
+	expectedSha256 string
+	versionUrl     string
+	hooks
 }
 
-func (f File) String() string {
+func (f *File) String() string {
 	return f.name
 }
 
-func (f File) Name() string {
+func (f *File) Name() string {
 	return f.name
 }
 
-func (f File) Priority() int {
+func (f *File) Priority() int {
 	return f.priority
 }
 
-func (f File) Type() ItemType {
+func (f *File) Type() ItemType {
 	return FileItem
 }
 
-func (f File) Handlers() ItemHandlers {
-	h := ItemHandlers{
-		Check:   fileChecker(f.name, f.url, f.dst),
-		Install: fileInstaller(f.url, f.dst, f.mode),
-		Update:  fileInstaller(f.url, f.dst, f.mode),
-	}
+func (f *File) Dependencies() []string {
+	return f.dependencies
+}
 
-	h.IsInstalled = func() (bool, error) {
-		home := utils.Home()
-		f.dst = strings.Replace(f.dst, "~", home, 1)
-		return utils.PathExists(f.dst), nil
+// Source returns the plugin manifest which declared this item, or
+// "" if it was declared in the main config.
+func (f *File) Source() string {
+	return f.source
+}
+
+// Version returns the upstream version recorded at install time, or
+// "" if it's never been installed or couldn't be resolved then.
+func (f *File) Version() (string, error) {
+	entry, ok, err := state.Get(f.name)
+	if err != nil || !ok {
+		return "", err
 	}
+	return entry.Version, nil
+}
 
-	h.Uninstall = func() error {
-		return sh.Runf("rm -f %s", f.dst)
+// LatestVersion resolves f's current upstream version, via version_url
+// if set, or the latest GitHub release tag when url points at one.
+func (f *File) LatestVersion() (string, error) {
+	fn := fileVersionResolver(f.name, f.url, f.versionUrl)
+	if fn == nil {
+		return "", NoVersionError{f.name}
 	}
+	return fn()
+}
+
+func (f *File) Check() error {
+	return fileCheck(f.name, f.url, f.dst)
+}
+
+func (f *File) IsInstalled() (bool, error) {
+	home := utils.Home()
+	dst := strings.Replace(f.dst, "~", home, 1)
+	return utils.PathExists(dst), nil
+}
+
+func (f *File) IsTainted() (bool, error) {
+	return fileTaintChecker(f.name, f.dst)()
+}
+
+func (f *File) Install() error {
+	versionFn := fileVersionResolver(f.name, f.url, f.versionUrl)
+	return fileInstaller(f.name, f.url, f.dst, f.mode, f.expectedSha256, versionFn)()
+}
+
+func (f *File) Update() error {
+	return f.Install()
+}
 
-	return h
+func (f *File) Uninstall() error {
+	return sh.Runf("rm -f %s", f.dst)
 }
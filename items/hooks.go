@@ -0,0 +1,30 @@
+package items
+
+import "github.com/mhmorgan/rogu/sh"
+
+// hooks holds the optional shell commands run around an item's
+// install/update/uninstall, and is embedded by the item types which
+// support them (File, scriptItem, Template). Embedding gives those
+// types the Hooked interface for free.
+type hooks struct {
+	preInstall    string
+	postInstall   string
+	preUpdate     string
+	postUpdate    string
+	preUninstall  string
+	postUninstall string
+}
+
+func (h hooks) PreInstall() error    { return runHook(h.preInstall) }
+func (h hooks) PostInstall() error   { return runHook(h.postInstall) }
+func (h hooks) PreUpdate() error     { return runHook(h.preUpdate) }
+func (h hooks) PostUpdate() error    { return runHook(h.postUpdate) }
+func (h hooks) PreUninstall() error  { return runHook(h.preUninstall) }
+func (h hooks) PostUninstall() error { return runHook(h.postUninstall) }
+
+func runHook(code string) error {
+	if code == "" {
+		return nil
+	}
+	return sh.Run(code)
+}
@@ -14,16 +14,27 @@ package items
 import (
 	"fmt"
 	"sort"
+	"strings"
 )
 
 type itemsFactory func() ([]Item, error)
 
 var itemsFactories []itemsFactory
 
+// registry and order are populated by All(), and give collections a
+// way to resolve and sequence their members without threading the
+// full item list through every call site.
+var (
+	registry map[string]Item
+	order    []string
+)
+
 // All returns a list of all items defined in the config.
 //
-// The list is sorted by priority, with the highest priority
-// items first.
+// The list is a stable topological sort over the items'
+// Dependencies(), so a dependency always precedes the items which
+// depend on it. Ties (including items with no dependencies at all)
+// are broken by descending Priority().
 func All() (items []Item, err error) {
 	for _, f := range itemsFactories {
 		if ii, err := f(); err != nil {
@@ -32,11 +43,79 @@ func All() (items []Item, err error) {
 			items = append(items, ii...)
 		}
 	}
-	items = append(items, Rogu{})
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].Priority() > items[j].Priority()
-	})
-	return items, nil
+	items = append(items, &Rogu{})
+
+	sorted, err := topoSort(items)
+	if err != nil {
+		return nil, err
+	}
+
+	registry = make(map[string]Item, len(sorted))
+	order = make([]string, len(sorted))
+	for i, it := range sorted {
+		registry[it.Name()] = it
+		order[i] = it.Name()
+	}
+	return sorted, nil
+}
+
+// topoSort orders items so dependencies precede their dependents,
+// using Kahn's algorithm. Items ready to be placed are picked in
+// descending Priority() order, so the result degrades to the old
+// priority-only ordering when no dependencies are declared.
+func topoSort(items []Item) ([]Item, error) {
+	byName := make(map[string]Item, len(items))
+	for _, it := range items {
+		byName[it.Name()] = it
+	}
+
+	indegree := make(map[string]int, len(items))
+	dependents := make(map[string][]string)
+	for _, it := range items {
+		for _, dep := range it.Dependencies() {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("%s: depends on unknown item %q", it.Name(), dep)
+			}
+			indegree[it.Name()]++
+			dependents[dep] = append(dependents[dep], it.Name())
+		}
+	}
+
+	var ready []string
+	for _, it := range items {
+		if indegree[it.Name()] == 0 {
+			ready = append(ready, it.Name())
+		}
+	}
+
+	sorted := make([]Item, 0, len(items))
+	for len(ready) > 0 {
+		sort.SliceStable(ready, func(i, j int) bool {
+			return byName[ready[i]].Priority() > byName[ready[j]].Priority()
+		})
+		name := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, byName[name])
+
+		for _, dependent := range dependents[name] {
+			indegree[dependent]--
+			if indegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(sorted) != len(items) {
+		var stuck []string
+		for name, deg := range indegree {
+			if deg > 0 {
+				stuck = append(stuck, name)
+			}
+		}
+		sort.Strings(stuck)
+		return nil, fmt.Errorf("dependency cycle detected among items: %s", strings.Join(stuck, ", "))
+	}
+	return sorted, nil
 }
 
 type ItemType int
@@ -47,30 +126,73 @@ const (
 	FileItem
 	ScriptItem
 	TemplateItem
+	CollectionItem
 )
 
 // Item
 
+// Item is implemented by every installable thing Rogu knows about:
+// scripts, files, templates, collections and Rogu itself. Install,
+// Update and Uninstall return a NoInstallerError/NoUpdaterError/
+// NoUninstallerError when the item doesn't support that action (e.g.
+// a script with no uninstall code), rather than being left out of the
+// interface, so callers (sync, doctor, uninstall) have one uniform
+// way to attempt an action and fall back on an unsupported one.
 type Item interface {
 	fmt.Stringer
 	Name() string
 	Priority() int // Higher numbers are higher priority.
 	Type() ItemType
-	Handlers() ItemHandlers
+	Dependencies() []string // Names of items which must be handled first.
+
+	Check() error
+	IsInstalled() (bool, error)
+	Install() error
+	Update() error
+	Uninstall() error
+}
+
+// Stasher is implemented by items which can set aside local changes
+// that would otherwise make them look broken, e.g. uncommitted
+// dotfiles. doctor's --fix uses it to offer a stash instead of
+// blindly overwriting anything.
+type Stasher interface {
+	Diff() (string, error)
+	Stash() error
+}
+
+// Sourced is implemented by items which know which file declared
+// them, e.g. items loaded from a plugin manifest. doctor's --verbose
+// uses it to trace where a definition came from.
+type Sourced interface {
+	Source() string
+}
+
+// Versioned is implemented by items which can report the version
+// they have installed and introspect their remote source for the
+// latest one. outdated and upgrade use it to tell what needs updating.
+type Versioned interface {
+	Version() (string, error)
+	LatestVersion() (string, error)
+}
+
+// Tainted is implemented by items which can report whether they've
+// been modified locally since Rogu installed them, e.g. by comparing
+// a content hash against the one recorded at install time. doctor and
+// sync's --force flag use it to detect items that shouldn't be
+// silently overwritten.
+type Tainted interface {
+	IsTainted() (bool, error)
 }
 
-type Fn func() error
-type BoolFn func() (bool, error)
-
-type ItemHandlers struct {
-	// Check runs any number of checks to determine if
-	// the item is OK.
-	//
-	// The checks can be: is the item installed, is the
-	// item up to date, etc.
-	Check       Fn
-	IsInstalled BoolFn
-	Install     Fn
-	Uninstall   Fn
-	Update      Fn
+// Hooked is implemented by items which support shell hooks run around
+// their lifecycle actions. sync runs these around the Install/Update
+// it performs; a hook that isn't configured is a no-op.
+type Hooked interface {
+	PreInstall() error
+	PostInstall() error
+	PreUpdate() error
+	PostUpdate() error
+	PreUninstall() error
+	PostUninstall() error
 }
@@ -0,0 +1,103 @@
+package items
+
+import (
+	"fmt"
+	"github.com/mhmorgan/rogu/config"
+	"github.com/mhmorgan/rogu/utils"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func init() {
+	itemsFactories = append(itemsFactories, pluginItems)
+}
+
+// PluginDir returns the directory Rogu scans for externally declared
+// items, following the pattern used by Helm's plugin.FindPlugins, so
+// third parties can add File/Script/Template items without touching
+// the main ~/.rogu config.
+//
+// It's resolved in this order: the ROGU_PLUGIN_DIR env var, the
+// plugins.dir config key, then ~/.rogu.d/.
+func PluginDir() string {
+	if dir := os.Getenv("ROGU_PLUGIN_DIR"); dir != "" {
+		return dir
+	}
+	if dir := config.Get().Plugins.Dir; dir != "" {
+		return dir
+	}
+	return utils.RelHome(".rogu.d")
+}
+
+// PluginFiles returns the YAML manifests found directly in
+// PluginDir(), sorted by path. A missing plugin directory is not an
+// error; it simply yields no files.
+func PluginFiles() ([]string, error) {
+	dir := PluginDir()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if name := e.Name(); strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// pluginItems loads every manifest in PluginDir() and builds the
+// items it declares, the same way the main config does, but tagging
+// each item with the manifest it came from.
+func pluginItems() (items []Item, err error) {
+	files, err := PluginFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		cfg, err := loadPlugin(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, builder := range []func(*config.Config, string) ([]Item, error){
+			filesFrom,
+			scriptsFrom,
+			templatesFrom,
+			collectionsFrom,
+		} {
+			ii, err := builder(cfg, file)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, ii...)
+		}
+	}
+	return
+}
+
+func loadPlugin(file string) (*config.Config, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cfg config.Config
+	if err := yaml.NewDecoder(f).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return &cfg, nil
+}
@@ -8,30 +8,48 @@ const roguItemName = "Rogu"
 
 type Rogu struct{}
 
-func (r Rogu) String() string {
+func (r *Rogu) String() string {
 	return roguItemName
 }
 
-func (r Rogu) Name() string {
+func (r *Rogu) Name() string {
 	return roguItemName
 }
 
-func (r Rogu) Priority() int {
+func (r *Rogu) Priority() int {
 	return 666
 }
 
-func (r Rogu) Type() ItemType {
+func (r *Rogu) Type() ItemType {
 	return RoguItem
 }
 
-func (r Rogu) Handlers() ItemHandlers {
+func (r *Rogu) Dependencies() []string {
+	return nil
+}
+
+func (r *Rogu) Check() error {
 	cfg := config.Get()
-	h := ItemHandlers{
-		Check:       fileChecker(roguItemName, cfg.RoguUrl(), cfg.Rogu.Path),
-		IsInstalled: func() (bool, error) { return true, nil },
-		Install:     fileInstaller(cfg.RoguUrl(), cfg.Rogu.Path, 0755),
-		Update:      fileInstaller(cfg.RoguUrl(), cfg.Rogu.Path, 0755),
-	}
-
-	return h
+	return fileCheck(roguItemName, cfg.RoguUrl(), cfg.Rogu.Path)
+}
+
+func (r *Rogu) IsInstalled() (bool, error) {
+	return true, nil
+}
+
+func (r *Rogu) IsTainted() (bool, error) {
+	return fileTaintChecker(roguItemName, config.Get().Rogu.Path)()
+}
+
+func (r *Rogu) Install() error {
+	cfg := config.Get()
+	return fileInstaller(roguItemName, cfg.RoguUrl(), cfg.Rogu.Path, 0755, "", nil)()
+}
+
+func (r *Rogu) Update() error {
+	return r.Install()
+}
+
+func (r *Rogu) Uninstall() error {
+	return NoUninstallerError{roguItemName}
 }
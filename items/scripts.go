@@ -8,12 +8,14 @@ import (
 )
 
 func init() {
-	itemsFactories = append(itemsFactories, scriptItems)
+	itemsFactories = append(itemsFactories, func() ([]Item, error) {
+		return scriptsFrom(config.Get(), "")
+	})
 }
 
-func scriptItems() (items []Item, err error) {
-	cfg := config.Get()
-
+// scriptsFrom builds the script items declared in cfg. source records
+// which file declared them (empty for the main ~/.rogu config).
+func scriptsFrom(cfg *config.Config, source string) (items []Item, err error) {
 	for name, val := range cfg.Scripts {
 		item := scriptItem{
 			name:              name,
@@ -24,8 +26,18 @@ func scriptItems() (items []Item, err error) {
 			uninstallCode:     val.Uninstall,
 			updateCode:        val.Update,
 			updateWithInstall: val.UpdateWithInstall,
+			dependencies:      val.Deps(),
+			source:            source,
+			hooks: hooks{
+				preInstall:    val.PreInstall,
+				postInstall:   val.PostInstall,
+				preUpdate:     val.PreUpdate,
+				postUpdate:    val.PostUpdate,
+				preUninstall:  val.PreUninstall,
+				postUninstall: val.PostUninstall,
+			},
 		}
-		items = append(items, item)
+		items = append(items, &item)
 	}
 	return
 }
@@ -41,105 +53,84 @@ type scriptItem struct {
 	uninstallCode     string // Optional
 	updateCode        string // Optional
 	updateWithInstall bool
+	dependencies      []string
+	source            string
+	hooks
 }
 
-func (i scriptItem) Name() string {
+func (i *scriptItem) Name() string {
 	return i.name
 }
 
-func (i scriptItem) Priority() int {
+func (i *scriptItem) Priority() int {
 	return i.priority
 }
 
-func (i scriptItem) Type() ItemType {
+func (i *scriptItem) Type() ItemType {
 	return ScriptItem
 }
 
-func (i scriptItem) Handlers() ItemHandlers {
-	h := ItemHandlers{
-		IsInstalled: i.isInstalled(),
-		Check:       i.checker(),
-		Install:     i.installer(),
-		Uninstall:   i.uninstaller(),
-		Update:      i.updater(),
-	}
-	if i.updateWithInstall {
-		h.Update = h.Install
-	}
-	return h
+func (i *scriptItem) Dependencies() []string {
+	return i.dependencies
+}
+
+func (i *scriptItem) Source() string {
+	return i.source
 }
 
-// checker returns a function which will checker the script item.
-//
-// The checks are:
-//  1. Check if the script has isInstalledCode
-//  2. Run the checkCode or, if empty, the isInstalledCode
-func (i scriptItem) checker() Fn {
-	var code string
-	if i.checkCode != "" {
-		code = i.checkCode
-	} else {
+func (i *scriptItem) String() string {
+	return i.name
+}
+
+// Check runs checkCode or, if empty, isInstalledCode.
+func (i *scriptItem) Check() error {
+	code := i.checkCode
+	if code == "" {
 		code = i.isInstalledCode
 	}
+	if code == "" {
+		return NoInstalledCheckError{i.name}
+	}
 
-	return func() error {
-		if i.installCode == "" {
-			return NoInstalledCheckError{i.name}
-		}
-		b, exitCode, err := sh.Exec(code)
-		if err != nil {
-			return err
-		}
-		if exitCode != 0 {
-			s := fmt.Sprintf("Check failed with exit code %d", exitCode)
-			if b.Len() > 0 {
-				s += " and output:\n" + b.String()
-			}
-			return errors.New(s)
+	b, exitCode, err := sh.Exec(code)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		s := fmt.Sprintf("Check failed with exit code %d", exitCode)
+		if b.Len() > 0 {
+			s += " and output:\n" + b.String()
 		}
-		return nil
+		return errors.New(s)
 	}
+	return nil
 }
 
-// isInstalled returns a function which will execute
-// the isInstalledCode.
-func (i scriptItem) isInstalled() BoolFn {
-	if i.isInstalledCode != "" {
-		return nil
-	}
-	return func() (bool, error) {
-		_, code, err := sh.Exec(i.isInstalledCode)
-		return code == 0, err
-	}
+func (i *scriptItem) IsInstalled() (bool, error) {
+	_, code, err := sh.Exec(i.isInstalledCode)
+	return code == 0, err
 }
 
-func (i scriptItem) installer() func() error {
+func (i *scriptItem) Install() error {
 	if i.installCode == "" {
-		return nil
-	}
-	return func() error {
-		return sh.Run(i.installCode)
+		return NoInstallerError{i.name}
 	}
+	return sh.Run(i.installCode)
 }
 
-func (i scriptItem) uninstaller() func() error {
+func (i *scriptItem) Uninstall() error {
 	if i.uninstallCode == "" {
-		return nil
-	}
-	return func() error {
-		return sh.Run(i.uninstallCode)
+		return NoUninstallerError{i.name}
 	}
+	return sh.Run(i.uninstallCode)
 }
 
-func (i scriptItem) updater() func() error {
-	if i.updateCode == "" {
-		return nil
+func (i *scriptItem) Update() error {
+	if i.updateWithInstall {
+		return i.Install()
 	}
-	return func() error {
-		return sh.Run(i.updateCode)
+	if i.updateCode == "" {
+		return NoUpdaterError{i.name}
 	}
-}
-
-func (i scriptItem) String() string {
-	return i.name
+	return sh.Run(i.updateCode)
 }
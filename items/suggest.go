@@ -0,0 +1,54 @@
+package items
+
+// suggestThreshold is the maximum Levenshtein distance a name may have
+// to be offered as a "did you mean?" suggestion.
+const suggestThreshold = 7
+
+// SuggestNearest returns the known item name closest to name by
+// Levenshtein distance, for use in "can't find X, did you mean Y?"
+// messages. It returns "" if there's no known item within
+// suggestThreshold edits, or if All() hasn't been called yet.
+func SuggestNearest(name string) string {
+	var best string
+	bestDist := suggestThreshold + 1
+	for _, candidate := range order {
+		if d := levenshtein(name, candidate); d < bestDist {
+			best = candidate
+			bestDist = d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(curr[j-1]+1, prev[j]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
@@ -14,98 +14,123 @@ func init() {
 }
 
 func TemplateItems() (items []Item, err error) {
-	for name, val := range config.Get().Templates {
+	return templatesFrom(config.Get(), "")
+}
+
+// templatesFrom builds the Template items declared in cfg. source
+// records which file declared them (empty for the main ~/.rogu
+// config).
+func templatesFrom(cfg *config.Config, source string) (items []Item, err error) {
+	for name, val := range cfg.Templates {
 		item := Template{
-			name:     name,
-			priority: val.Priority,
-			url:      val.Url,
-			branch:   val.Branch,
+			name:         name,
+			priority:     val.Priority,
+			url:          val.Url,
+			branch:       val.Branch,
+			dependencies: val.Deps(),
+			source:       source,
+			hooks: hooks{
+				preInstall:    val.PreInstall,
+				postInstall:   val.PostInstall,
+				preUpdate:     val.PreUpdate,
+				postUpdate:    val.PostUpdate,
+				preUninstall:  val.PreUninstall,
+				postUninstall: val.PostUninstall,
+			},
 		}
 		if item.branch == "" {
 			item.branch = config.DefaultBranch
 		}
-		items = append(items, item)
+		items = append(items, &item)
 	}
 	return
 }
 
 type Template struct {
-	priority int
-	name     string
-	url      string
-	branch   string
+	priority     int
+	name         string
+	url          string
+	branch       string
+	dependencies []string
+	source       string
+	hooks
 }
 
-func (t Template) String() string {
+func (t *Template) String() string {
 	return fmt.Sprintf("Template %q", t.name)
 }
 
-func (t Template) Name() string {
+func (t *Template) Name() string {
 	return t.name
 }
 
-func (t Template) Priority() int {
+func (t *Template) Priority() int {
 	return t.priority
 }
 
-func (t Template) Type() ItemType {
+func (t *Template) Type() ItemType {
 	return TemplateItem
 }
 
-func (t Template) Handlers() ItemHandlers {
-	return ItemHandlers{
-		Check:       t.checker(),
-		IsInstalled: t.isInstalled(),
-		Install:     t.installer(),
-		Update:      t.updater(),
+func (t *Template) Dependencies() []string {
+	return t.dependencies
+}
+
+func (t *Template) Source() string {
+	return t.source
+}
+
+// Version returns the commit SHA currently checked out.
+func (t *Template) Version() (string, error) {
+	repo, err := git.Open(t.Root(), git.WithBranch(t.branch))
+	if err != nil {
+		return "", err
 	}
+	return repo.Head()
+}
+
+// LatestVersion returns the commit SHA at the tip of t's branch on
+// its remote, via git ls-remote.
+func (t *Template) LatestVersion() (string, error) {
+	return git.LsRemote(t.url, t.branch)
 }
 
-func (t Template) Root() string {
+func (t *Template) Root() string {
 	base := "." + strings.TrimSuffix(filepath.Base(t.url), ".git")
 	home := utils.Home()
 	return filepath.Join(home, base)
 }
 
-func (t Template) checker() Fn {
-	return func() error {
-		repo, err := git.Open(t.Root(), git.WithBranch(t.branch))
-		if err != nil {
-			return err
-		}
-		utils.Cd(t.Root())
+func (t *Template) Check() error {
+	repo, err := git.Open(t.Root(), git.WithBranch(t.branch))
+	if err != nil {
+		return err
+	}
 
-		if files, err := repo.ModifiedFiles(); err != nil {
-			return err
-		} else if len(files) > 0 {
-			return fmt.Errorf("%s has uncommitted changes", repo)
-		}
-		return nil
+	if files, err := repo.ModifiedFiles(); err != nil {
+		return err
+	} else if len(files) > 0 {
+		return fmt.Errorf("%s has uncommitted changes", repo)
 	}
+	return nil
 }
 
-func (t Template) installer() Fn {
-	return func() error {
-		if err := git.Clone(t.url, t.Root()); err != nil {
-			return err
-		}
-		return nil
-	}
+func (t *Template) IsInstalled() (bool, error) {
+	return utils.PathExists(t.Root()), nil
 }
 
-func (t Template) updater() Fn {
-	return func() error {
-		repo, err := git.Open(t.Root(), git.WithBranch(t.branch))
-		if err != nil {
-			return err
-		}
-		utils.Cd(t.Root())
-		return repo.Sync()
-	}
+func (t *Template) Install() error {
+	return git.Clone(t.url, t.Root())
 }
 
-func (t Template) isInstalled() BoolFn {
-	return func() (bool, error) {
-		return utils.PathExists(t.Root()), nil
+func (t *Template) Update() error {
+	repo, err := git.Open(t.Root(), git.WithBranch(t.branch))
+	if err != nil {
+		return err
 	}
+	return repo.Sync()
+}
+
+func (t *Template) Uninstall() error {
+	return NoUninstallerError{t.name}
 }
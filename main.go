@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"github.com/mhmorgan/rogu/config"
+	"github.com/mhmorgan/rogu/items"
 	"github.com/mhmorgan/rogu/utils"
 	log "github.com/mhmorgan/termlog"
 	"os"
@@ -99,6 +100,29 @@ func nameMatch(name string, ss []string) bool {
 	return false
 }
 
+// checkFilters exits with a "did you mean?" suggestion for the first
+// filter in args which matches no item in itms.
+func checkFilters(itms []items.Item, args []string) {
+	for _, arg := range args {
+		matched := false
+		for _, item := range itms {
+			if nameMatch(item.Name(), []string{arg}) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if suggestion := items.SuggestNearest(arg); suggestion != "" {
+			log.Errorf("can't find %q, did you mean %q?", arg, suggestion)
+		} else {
+			log.Errorf("can't find %q", arg)
+		}
+		os.Exit(1)
+	}
+}
+
 const mainUsage = `usage: rogu <command> [arguments]
 
  ____                   
@@ -116,10 +140,18 @@ The commands are:
     doctor       diagnose your machine
     help         show this help
     init         initialize a new git repository
+    install      install an item from the hub
     list	     list all dotfiles
+    outdated     report items with a newer upstream version
+    plugin       manage externally declared items
+    search       search the hub for items by name
+    show         show a hub index entry
     sync         install and update Rogu's items
+    uninstall    uninstall one or more of Rogu's items
+    upgrade      upgrade outdated items
 
 Environment variables:
 
-    ROGU_CONFIG  Path to config file (default: ~/.rogu)
+    ROGU_CONFIG     Path to config file (default: ~/.rogu)
+    ROGU_PLUGIN_DIR Path to plugin directory (default: ~/.rogu.d)
 `
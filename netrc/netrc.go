@@ -0,0 +1,67 @@
+// Package netrc implements a minimal reader for ~/.netrc, just enough
+// to resolve the login/password pair for a given machine (host). It's
+// used as a fallback credential source for git operations against
+// private HTTPS repositories, for hosts not overridden in
+// config.Config.Auth.
+package netrc
+
+import (
+	"bufio"
+	"github.com/mhmorgan/rogu/utils"
+	"io"
+	"os"
+)
+
+// Entry holds the login/password pair for a single "machine" entry.
+type Entry struct {
+	Login    string
+	Password string
+}
+
+// Lookup reads ~/.netrc and returns the entry for host, if any.
+func Lookup(host string) (Entry, bool) {
+	f, err := os.Open(utils.RelHome(".netrc"))
+	if err != nil {
+		return Entry{}, false
+	}
+	defer f.Close()
+	return find(f, host)
+}
+
+// find scans r for a "machine host login ... password ..." entry and
+// returns its login/password fields. Only the fields netrc lines
+// actually need (machine, login, password) are recognised; others
+// (account, macdef, default) are ignored.
+func find(r io.Reader, host string) (e Entry, ok bool) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+
+	var tokens []string
+	for scanner.Scan() {
+		tokens = append(tokens, scanner.Text())
+	}
+
+	var current string
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "machine":
+			if i+1 < len(tokens) {
+				current = tokens[i+1]
+				i++
+			}
+		case "login":
+			if current == host && i+1 < len(tokens) {
+				e.Login = tokens[i+1]
+				ok = true
+				i++
+			}
+		case "password":
+			if current == host && i+1 < len(tokens) {
+				e.Password = tokens[i+1]
+				ok = true
+				i++
+			}
+		}
+	}
+	return
+}
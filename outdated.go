@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/mhmorgan/rogu/items"
+	log "github.com/mhmorgan/termlog"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+func init() {
+	commands["outdated"] = &command{outdatedFlags, outdated}
+}
+
+func outdatedFlags(flags *flag.FlagSet) {
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, outdatedUsage)
+		flags.PrintDefaults()
+		os.Exit(2)
+	}
+}
+
+func outdated(args []string) {
+	itms, err := items.All()
+	if err != nil {
+		log.Fatal(err)
+	}
+	checkFilters(itms, args)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tINSTALLED\tLATEST\tDEPENDENCIES")
+	for _, item := range itms {
+		if !nameMatch(item.Name(), args) {
+			continue
+		}
+		v, ok := item.(items.Versioned)
+		if !ok {
+			continue
+		}
+
+		installed, err := v.Version()
+		if err != nil {
+			log.Errorf("%v Version: %v", item, err)
+			continue
+		}
+		latest, err := v.LatestVersion()
+		var noVersion items.NoVersionError
+		if errors.As(err, &noVersion) {
+			continue
+		} else if err != nil {
+			log.Errorf("%v LatestVersion: %v", item, err)
+			continue
+		}
+		if installed == latest {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Name(), installed, latest, strings.Join(item.Dependencies(), ", "))
+	}
+	w.Flush()
+}
+
+const outdatedUsage = `usage: rogu outdated [filters...]
+
+Report items whose installed version differs from the
+latest version available upstream.
+
+If filters are given only items matching the filters will
+be checked.
+
+Options:
+`
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/mhmorgan/rogu/items"
+	log "github.com/mhmorgan/termlog"
+	"os"
+)
+
+func init() {
+	commands["plugin"] = &command{pluginFlags, plugin}
+}
+
+func pluginFlags(flags *flag.FlagSet) {
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, pluginUsage)
+		flags.PrintDefaults()
+		os.Exit(2)
+	}
+}
+
+func plugin(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, pluginUsage)
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "path":
+		fmt.Println(items.PluginDir())
+	case "list":
+		files, err := items.PluginFiles()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, file := range files {
+			fmt.Println(file)
+		}
+	default:
+		log.Fatalf("unknown plugin subcommand %q", args[0])
+	}
+}
+
+const pluginUsage = `usage: rogu plugin <list|path>
+
+Manage the directory Rogu scans for externally declared
+items, so third parties can add File/Script/Template items
+without editing the main config.
+
+    list   list the plugin manifest files Rogu will load
+    path   print the plugin directory path
+
+Options:
+`
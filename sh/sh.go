@@ -44,3 +44,20 @@ func Exec(s string) (b bytes.Buffer, code int, err error) {
 func Execf(format string, a ...interface{}) (bytes.Buffer, int, error) {
 	return Exec(fmt.Sprintf(format, a...))
 }
+
+// ExecIn runs a shell command in dir and returns the stdout and
+// stderr combined into a single buffer. Unlike Exec, it sets the
+// command's working directory directly instead of chdir'ing the
+// process, so it's safe to call from concurrent goroutines.
+func ExecIn(dir, s string) (b bytes.Buffer, code int, err error) {
+	c := cmd.NewCommand(s, cmd.WithCustomStdout(&b), cmd.WithCustomStderr(&b), cmd.WithWorkingDir(dir))
+	err = c.Execute()
+	code = c.ExitCode()
+	return
+}
+
+// ExecInf runs a shell command in dir, like ExecIn, formatting the
+// command with Sprintf first.
+func ExecInf(dir, format string, a ...interface{}) (bytes.Buffer, int, error) {
+	return ExecIn(dir, fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,88 @@
+// Package state persists what Rogu actually installed for each item,
+// so later runs can tell a file apart that's missing from one that's
+// merely been modified locally (tainted), without re-downloading or
+// re-running anything.
+package state
+
+import (
+	"encoding/json"
+	"github.com/mhmorgan/rogu/utils"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry records what was installed for a single item.
+type Entry struct {
+	Sha256      string    `json:"sha256,omitempty"`
+	Url         string    `json:"url,omitempty"`
+	Mode        int       `json:"mode,omitempty"`
+	InstalledAt time.Time `json:"installed_at,omitempty"`
+	// Version is the upstream version that was installed, as reported
+	// by the item's LatestVersion() at install time.
+	Version string `json:"version,omitempty"`
+}
+
+var mu sync.Mutex
+
+func path() string {
+	return filepath.Join(utils.Home(), ".cache", "rogu", "state.json")
+}
+
+// Load returns the full persisted state, keyed by item name. A
+// missing state file is not an error; it simply yields an empty map.
+func Load() (map[string]Entry, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return load()
+}
+
+func load() (map[string]Entry, error) {
+	state := make(map[string]Entry)
+	b, err := os.ReadFile(path())
+	if os.IsNotExist(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Get returns the recorded entry for name, if any.
+func Get(name string) (entry Entry, ok bool, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+	state, err := load()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	entry, ok = state[name]
+	return
+}
+
+// Set persists entry as the recorded state for name.
+func Set(name string, entry Entry) error {
+	mu.Lock()
+	defer mu.Unlock()
+	state, err := load()
+	if err != nil {
+		return err
+	}
+	state[name] = entry
+	return save(state)
+}
+
+func save(state map[string]Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path()), 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path(), b, 0644)
+}
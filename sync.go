@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/mhmorgan/rogu/config"
@@ -11,6 +12,7 @@ import (
 
 var (
 	updateRogu bool
+	force      bool
 )
 
 func init() {
@@ -24,6 +26,7 @@ func syncFlags(flags *flag.FlagSet) {
 		os.Exit(2)
 	}
 	flags.BoolVar(&updateRogu, "rogu", false, "sync rogu himself")
+	flags.BoolVar(&force, "force", false, "overwrite tainted (locally modified) files")
 }
 
 func sync(args []string) {
@@ -31,6 +34,7 @@ func sync(args []string) {
 	if ii, err := items.All(); err != nil {
 		log.Fatal(err)
 	} else {
+		checkFilters(ii, args)
 		for _, item := range ii {
 			if !(updateRogu || item.Type() != items.RoguItem) {
 				continue
@@ -42,35 +46,73 @@ func sync(args []string) {
 	}
 
 	for _, item := range itms {
-		var pri string
-		if config.Bool("verbose") {
-			pri = fmt.Sprintf("(%3d) ", item.Priority())
-		}
-
-		h := item.Handlers()
-		if h.IsInstalled == nil {
-			log.Fatalf("%s%s has no installation check", pri, item.Name())
-		}
-		installed, err := h.IsInstalled()
-		if err != nil {
+		if err := syncItem(item); err != nil {
 			log.Fatal(err)
 		}
+	}
+}
 
-		if !installed {
-			if h.Install != nil {
-				log.Emphf("%sInstalling %s", pri, item)
-				err = h.Install()
-			} else {
-				log.Warnf("%sI don't know how to install %s", pri, item)
-			}
-		} else if h.Update != nil {
-			log.Infof("%sUpdating %s", pri, item)
-			err = h.Update()
+// syncItem installs item if it isn't installed, or updates it
+// otherwise, honoring tainted detection and pre/post hooks. Used by
+// both sync's main loop and hub install, which syncs a single freshly
+// added item the same way.
+func syncItem(item items.Item) error {
+	var pri string
+	if config.Bool("verbose") {
+		pri = fmt.Sprintf("(%3d) ", item.Priority())
+	}
+
+	installed, err := item.IsInstalled()
+	if err != nil {
+		return err
+	}
+
+	if !installed {
+		err = withHooks(item, items.Hooked.PreInstall, items.Hooked.PostInstall, item.Install)
+		var noInstaller items.NoInstallerError
+		if errors.As(err, &noInstaller) {
+			log.Warnf("%sI don't know how to install %s", pri, item)
+			return nil
+		} else if err == nil {
+			log.Emphf("%sInstalled %s", pri, item)
 		}
+		return err
+	}
+
+	if tainter, ok := item.(items.Tainted); ok && !force {
+		tainted, err := tainter.IsTainted()
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
+		if tainted {
+			log.Warnf("%s%s is tainted (modified locally), skipping update (use --force to overwrite)", pri, item)
+			return nil
+		}
+	}
+	err = withHooks(item, items.Hooked.PreUpdate, items.Hooked.PostUpdate, item.Update)
+	var noUpdater items.NoUpdaterError
+	if errors.As(err, &noUpdater) {
+		return nil
+	} else if err == nil {
+		log.Infof("%sUpdated %s", pri, item)
+	}
+	return err
+}
+
+// withHooks runs action wrapped by item's pre/post hooks for the
+// given lifecycle stage, if item implements items.Hooked.
+func withHooks(item items.Item, pre, post func(items.Hooked) error, action func() error) error {
+	hooked, ok := item.(items.Hooked)
+	if !ok {
+		return action()
+	}
+	if err := pre(hooked); err != nil {
+		return err
+	}
+	if err := action(); err != nil {
+		return err
 	}
+	return post(hooked)
 }
 
 const syncUsage = `usage: rogu sync [options] [filters...]
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/mhmorgan/rogu/items"
+	log "github.com/mhmorgan/termlog"
+	"os"
+)
+
+func init() {
+	commands["uninstall"] = &command{uninstallFlags, uninstall}
+	commands["rm"] = commands["uninstall"]
+}
+
+func uninstallFlags(flags *flag.FlagSet) {
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, uninstallUsage)
+		flags.PrintDefaults()
+		os.Exit(2)
+	}
+	flags.BoolVar(&yes, "yes", false, "don't ask for confirmation before uninstalling")
+}
+
+func uninstall(args []string) {
+	if len(args) == 0 {
+		log.Fatal("at least one filter is required, to avoid accidentally uninstalling everything")
+	}
+
+	itms, err := items.All()
+	if err != nil {
+		log.Fatal(err)
+	}
+	checkFilters(itms, args)
+
+	for _, item := range itms {
+		if nameMatch(item.Name(), args) {
+			uninstallItem(item)
+		}
+	}
+}
+
+func uninstallItem(item items.Item) {
+	if !confirm(fmt.Sprintf("Uninstall %v?", item)) {
+		return
+	}
+	log.Emphf("Uninstalling %v", item)
+	err := withHooks(item, items.Hooked.PreUninstall, items.Hooked.PostUninstall, item.Uninstall)
+	var noUninstaller items.NoUninstallerError
+	if errors.As(err, &noUninstaller) {
+		log.Warnf("I don't know how to uninstall %v", item)
+	} else if err != nil {
+		log.Error(err)
+	}
+}
+
+const uninstallUsage = `usage: rogu uninstall [options] filters...
+
+Uninstall removes items Rogu knows how to uninstall.
+
+At least one FILTER must be given, selecting items by name,
+to avoid accidentally uninstalling everything.
+
+Options:
+`
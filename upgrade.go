@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/mhmorgan/rogu/items"
+	log "github.com/mhmorgan/termlog"
+	"os"
+)
+
+func init() {
+	commands["upgrade"] = &command{upgradeFlags, upgrade}
+}
+
+func upgradeFlags(flags *flag.FlagSet) {
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, upgradeUsage)
+		flags.PrintDefaults()
+		os.Exit(2)
+	}
+}
+
+func upgrade(args []string) {
+	itms, err := items.All()
+	if err != nil {
+		log.Fatal(err)
+	}
+	checkFilters(itms, args)
+
+	for _, item := range itms {
+		if !nameMatch(item.Name(), args) {
+			continue
+		}
+		v, ok := item.(items.Versioned)
+		if !ok {
+			continue
+		}
+
+		installed, err := v.Version()
+		if err != nil {
+			log.Errorf("%v Version: %v", item, err)
+			continue
+		}
+		latest, err := v.LatestVersion()
+		var noVersion items.NoVersionError
+		if errors.As(err, &noVersion) {
+			continue
+		} else if err != nil {
+			log.Errorf("%v LatestVersion: %v", item, err)
+			continue
+		}
+		if installed == latest {
+			continue
+		}
+
+		log.Emphf("Upgrading %v (%s -> %s)", item, installed, latest)
+		err = withHooks(item, items.Hooked.PreUpdate, items.Hooked.PostUpdate, item.Update)
+		var noUpdater items.NoUpdaterError
+		if errors.As(err, &noUpdater) {
+			log.Warnf("I don't know how to update %v", item)
+		} else if err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+const upgradeUsage = `usage: rogu upgrade [filters...]
+
+Re-run Update for items whose installed version differs
+from the latest version available upstream.
+
+If filters are given only items matching the filters will
+be upgraded.
+
+Options:
+`
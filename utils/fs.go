@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"github.com/mhmorgan/rogu/sh"
 	log "github.com/mhmorgan/termlog"
+	"io"
 	"os"
 	"path/filepath"
 )
@@ -31,26 +34,6 @@ func RelHome(path string) string {
 	return filepath.Join(home, path)
 }
 
-// CdHome changes the current working directory to the
-// home directory of the current user, or calls log.Fatal
-// if it cannot be determined.
-func CdHome() {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		log.Fatal(err)
-	}
-	if err := os.Chdir(home); err != nil {
-		log.Fatal(err)
-	}
-}
-
-// Cd changes to the given directory, or calls log.Fatal.
-func Cd(path string) {
-	if err := os.Chdir(path); err != nil {
-		log.Fatal(err)
-	}
-}
-
 // PathExists returns true if the given path exists.
 func PathExists(path string) bool {
 	_, err := os.Stat(path)
@@ -63,10 +46,28 @@ func PathExists(path string) bool {
 	return false
 }
 
-// Backup backs up the given file by copying it to a new file
-// with the same name and a tilde appended.
-func Backup(path string) error {
+// Sha256 returns the hex-encoded SHA-256 checksum of the file at path.
+func Sha256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Backup backs up the file at path, relative to dir, by copying it
+// to a new file with the same name and a tilde appended. dir is
+// passed straight through to the shell via exec.Cmd.Dir rather than
+// chdir'ing the process, so it's safe to call from concurrent
+// goroutines.
+func Backup(dir, path string) error {
 	dst := path + "~"
-	_, _, err := sh.Execf("cp %s %s", path, dst)
+	_, _, err := sh.ExecInf(dir, "cp %s %s", path, dst)
 	return err
 }
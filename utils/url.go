@@ -1,17 +1,55 @@
 package utils
 
 import (
+	"bufio"
 	"github.com/mhmorgan/rogu/sh"
+	"net/http"
+	"net/textproto"
 	"strconv"
+	"strings"
 )
 
-func UrlExists(url string) (responseCode int, ok bool, err error) {
-	curl := "curl -sfIL -o /dev/null -w '%{http_code}' " + url
+// UrlExists checks whether url responds successfully, following
+// redirects. It returns the final response's status code, whether
+// curl itself succeeded, and the final response's headers, so
+// callers can do conditional requests via ETag/Last-Modified.
+func UrlExists(url string) (responseCode int, ok bool, headers http.Header, err error) {
+	curl := "curl -sfIL " + url
 	b, code, err := sh.Exec(curl)
 	if err != nil {
 		return
 	}
-	responseCode, err = strconv.Atoi(b.String())
 	ok = code == 0
+
+	responseCode, headers, err = parseHeaders(b.String())
 	return
 }
+
+// parseHeaders parses the header block(s) curl -I dumps to stdout
+// (one per hop when following redirects) and returns the status code
+// and headers of the last response in the chain.
+func parseHeaders(raw string) (int, http.Header, error) {
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+	blocks := strings.Split(strings.TrimRight(raw, "\n"), "\n\n")
+	if len(blocks) == 0 || strings.TrimSpace(blocks[len(blocks)-1]) == "" {
+		return 0, http.Header{}, nil
+	}
+	last := blocks[len(blocks)-1]
+
+	reader := textproto.NewReader(bufio.NewReader(strings.NewReader(last + "\n")))
+	statusLine, err := reader.ReadLine()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var code int
+	if fields := strings.Fields(statusLine); len(fields) >= 2 {
+		code, _ = strconv.Atoi(fields[1])
+	}
+
+	mimeHeader, err := reader.ReadMIMEHeader()
+	if err != nil && err.Error() != "EOF" {
+		return code, nil, err
+	}
+	return code, http.Header(mimeHeader), nil
+}